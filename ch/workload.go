@@ -60,6 +60,9 @@ type Workloader struct {
 	measurement *measurement.Measurement
 
 	PlanReplayerRunner *replayer.PlanReplayerRunner
+
+	// Comparer compares the results of the queries with another database if it's set.
+	Comparer *tpch.Comparer
 }
 
 // NewWorkloader new work loader
@@ -71,7 +74,7 @@ func NewWorkloader(db *sql.DB, cfg *Config) workload.Workloader {
 			m.MinLatency = 100 * time.Microsecond
 			m.MaxLatency = 20 * time.Minute
 			m.SigFigs = 3
-		}),
+		}, measurement.WithMetrics("ch")),
 	}
 }
 
@@ -231,10 +234,15 @@ func (w *Workloader) Run(ctx context.Context, threadID int) error {
 	if w.cfg.ExecExplainAnalyze {
 		query = strings.Replace(query, "/*PLACEHOLDER*/", "explain analyze", 1)
 	}
-	start := time.Now()
+	start := workload.StartTime(ctx)
+	// end is set before comparing the result to exclude the compare target from the measurement
+	var end time.Time
 	rows, err := s.Conn.QueryContext(ctx, query)
 	defer func() {
-		w.measurement.Measure(queryName, time.Since(start), err)
+		if end.IsZero() {
+			end = time.Now()
+		}
+		w.measurement.Measure(queryName, end.Sub(start), err)
 	}()
 	if err != nil {
 		// Check if error is due to context cancellation/timeout
@@ -253,6 +261,19 @@ func (w *Workloader) Run(ctx context.Context, threadID int) error {
 		util.StdErrLogger.Printf("explain analyze result of query %s (takes %s):\n%s\n", queryName, time.Since(start), table)
 		return nil
 	}
+	if w.Comparer != nil {
+		var (
+			cols []string
+			got  [][]string
+		)
+		cols, got, err = tpch.ScanRows(rows)
+		end = time.Now()
+		if err != nil {
+			return fmt.Errorf("execute query %s failed %v", queryName, err)
+		}
+		w.Comparer.Compare(ctx, queryName, query, cols, got)
+		return nil
+	}
 	if err := w.drainQueryResult(queryName, rows); err != nil {
 		return fmt.Errorf("execute query %s failed %v", queryName, err)
 	}
@@ -318,21 +339,37 @@ func chSummary(h *measurement.Histogram) []string {
 func (w *Workloader) OutputStats(ifSummaryReport bool) {
 	w.measurement.Output(ifSummaryReport, w.cfg.OutputStyle, outputRtMeasurement)
 	if ifSummaryReport {
-		var count int64
-		var sum float64
-		for _, m := range w.measurement.OpSumMeasurement {
-			if !m.Empty() {
-				r := m.GetInfo()
-				count += r.Count
-				sum += r.Sum
-			}
+		if results := w.Results(); results != nil {
+			fmt.Printf("QphH: %.1f\n", results["QphH"])
 		}
-		if sum != 0 {
-			fmt.Printf("QphH: %.1f\n", 3600*1000/sum*float64(count))
+		if w.Comparer != nil {
+			w.Comparer.OutputStats(w.cfg.OutputStyle)
 		}
 	}
 }
 
+// Results implements workload.ResultProvider, it returns QphH or nil if no query is measured.
+func (w *Workloader) Results() map[string]float64 {
+	var count int64
+	var sum float64
+	for _, m := range w.measurement.OpSumMeasurement {
+		if !m.Empty() {
+			r := m.GetInfo()
+			count += r.Count
+			sum += r.Sum
+		}
+	}
+	if sum == 0 {
+		return nil
+	}
+	return map[string]float64{"QphH": 3600 * 1000 / sum * float64(count)}
+}
+
+// Measurement returns the response time measurement of the workload.
+func (w *Workloader) Measurement() *measurement.Measurement {
+	return w.measurement
+}
+
 // DBName returns the name of test db.
 func (w *Workloader) DBName() string {
 	return w.cfg.DBName
@@ -371,6 +408,11 @@ func (w *Workloader) Exec(sql string) error {
 	}
 	defer s.Conn.Close()
 
-	_, err := s.Conn.ExecContext(ctx, sql)
-	return err
+	if _, err := s.Conn.ExecContext(ctx, sql); err != nil {
+		return err
+	}
+	if w.Comparer != nil {
+		return w.Comparer.Exec(sql)
+	}
+	return nil
 }
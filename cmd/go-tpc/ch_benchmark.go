@@ -112,12 +112,14 @@ func registerCHBenchmark(root *cobra.Command) {
 	cmdRun.Flags().StringVar(&apConnParams, "ap-conn-params", "", "Connection parameters for analytical processing")
 	cmdRun.Flags().StringSliceVar(&apHosts, "ap-host", nil, "Database host for analytical processing")
 	cmdRun.Flags().IntSliceVar(&apPorts, "ap-port", nil, "Database port for analytical processing")
+	registerCompareTarget(cmdRun)
 
 	cmd.AddCommand(cmdRun, cmdPrepare)
 	root.AddCommand(cmd)
 }
 
 func executeCH(action string, openAP func() (*sql.DB, error)) {
+	startHTTPServers()
 	if maxProcs != 0 {
 		runtime.GOMAXPROCS(maxProcs)
 	}
@@ -167,6 +169,10 @@ func executeCH(action string, openAP func() (*sql.DB, error)) {
 		}
 		db.SetMaxIdleConns(acThreads + 1)
 		ap = ch.NewWorkloader(db, &chConfig)
+		if comparer := openComparer(); comparer != nil {
+			ap.(*ch.Workloader).Comparer = comparer
+			defer exitOnMismatch(comparer)
+		}
 	}
 	if err != nil {
 		fmt.Printf("Failed to init tp work loader: %v\n", err)
@@ -197,4 +203,5 @@ func executeCH(action string, openAP func() (*sql.DB, error)) {
 	for _, workLoader := range []workLoaderSetting{{workLoader: tp, threads: threads}, {workLoader: ap, threads: acThreads}} {
 		workLoader.workLoader.OutputStats(true)
 	}
+	writeResult(tp, ap)
 }
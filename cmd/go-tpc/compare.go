@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var compareThresholds measurement.Thresholds
+
+func registerCompare(root *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "compare <baseline> <candidate>",
+		Short: "Compare the summaries of two runs and fail on regressions",
+		Long: `Compare the summaries of two runs and fail on regressions.
+Each file is either a --result-file document or the captured stdout of a run with --output json.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !executeCompare(args[0], args[1]) {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Float64Var(&compareThresholds.MaxOpsDrop, "max-ops-drop", 5, "Max allowed throughput drop in percent")
+	cmd.Flags().Float64Var(&compareThresholds.MaxLatencyIncrease, "max-latency-increase", 10, "Max allowed avg/p50/p95/p99 latency increase in percent")
+	root.AddCommand(cmd)
+}
+
+// executeCompare prints per-operation deltas and returns false if any regression is found.
+func executeCompare(baselineFile, candidateFile string) bool {
+	baseline, err := loadSummary(baselineFile)
+	if err != nil {
+		util.StdErrLogger.Printf("load baseline %s failed: %v", baselineFile, err)
+		os.Exit(1)
+	}
+	candidate, err := loadSummary(candidateFile)
+	if err != nil {
+		util.StdErrLogger.Printf("load candidate %s failed: %v", candidateFile, err)
+		os.Exit(1)
+	}
+
+	ops := make([]string, 0, len(baseline))
+	for op := range baseline {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	passed := true
+	lines := [][]string{}
+	var unknownOps []string
+	for _, op := range ops {
+		cand, ok := candidate[op]
+		if !ok {
+			passed = false
+			lines = append(lines, []string{"[Compare] ", op, "-", "-", "-", "-", "-", "-", "-", "missing in candidate"})
+			continue
+		}
+		d := measurement.Compare(op, baseline[op], cand)
+		verdict := "ok"
+		if reasons := d.Regressions(compareThresholds); len(reasons) > 0 {
+			passed = false
+			verdict = "REGRESSION: " + strings.Join(reasons, "; ")
+		}
+		baseOps, candOps, opsChange := util.FloatToTwoString(d.Baseline.Ops), util.FloatToTwoString(d.Candidate.Ops), formatChange(d.Ops)
+		if math.IsNaN(d.Baseline.Ops) || math.IsNaN(d.Candidate.Ops) {
+			unknownOps = append(unknownOps, op)
+			baseOps, candOps, opsChange = "-", "-", "-"
+		}
+		lines = append(lines, []string{"[Compare] ", op, baseOps, candOps, opsChange,
+			formatChange(d.Avg), formatChange(d.P50), formatChange(d.P95), formatChange(d.P99), verdict})
+	}
+
+	headers := []string{"Prefix", "Operation", "Base OPS", "Cand OPS", "OPS", "Avg", "50th", "95th", "99th", "Result"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", headers, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+	if len(unknownOps) > 0 {
+		util.StdErrLogger.Printf("WARNING: the throughput of %s is not compared since the summaries have no TPM, "+
+			"compare the --result-file documents of the runs instead", strings.Join(unknownOps, ", "))
+	}
+	return passed
+}
+
+func formatChange(f float64) string {
+	return fmt.Sprintf("%+.1f%%", f)
+}
+
+// loadSummary loads the summary of every operation, keyed by upper case operation name.
+// Error operations are skipped since they are not comparable.
+func loadSummary(path string) (map[string]measurement.HistInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := make(map[string]measurement.HistInfo)
+	add := func(op string, info measurement.HistInfo) {
+		op = strings.ToUpper(op)
+		if !strings.HasSuffix(op, "_ERR") {
+			summary[op] = info
+		}
+	}
+
+	var doc result
+	if err := json.Unmarshal(data, &doc); err == nil && len(doc.Workloads) > 0 {
+		for _, wr := range doc.Workloads {
+			for op, info := range wr.Summary {
+				if len(doc.Workloads) > 1 {
+					op = wr.Name + "/" + op
+				}
+				add(op, info)
+			}
+		}
+		return summary, nil
+	}
+
+	// Captured stdout of --output json, every rendered table is one JSON line.
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("[{")) {
+			continue
+		}
+		var rows []map[string]string
+		if err := json.Unmarshal(line, &rows); err != nil {
+			continue
+		}
+		for _, row := range rows {
+			if row["Prefix"] == "[Summary] " && len(row["Operation"]) > 0 {
+				add(row["Operation"], parseRenderedInfo(row))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(summary) == 0 {
+		return nil, fmt.Errorf("no summary found")
+	}
+	return summary, nil
+}
+
+// parseRenderedInfo converts a row rendered by util.RenderJson back to HistInfo. The throughput is
+// NaN if the row has no TPM, e.g. the summaries of tpch, ch, rawsql and ssb only have the latency.
+func parseRenderedInfo(row map[string]string) measurement.HistInfo {
+	parse := func(key string) float64 {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(row[key], "s"), 64)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	info := measurement.HistInfo{
+		Elapsed: parse("Takes(s)"),
+		Count:   int64(parse("Count")),
+		Ops:     math.NaN(),
+		Sum:     parse("Sum(ms)"),
+		Avg:     parse("Avg(ms)"),
+		P50:     parse("50th(ms)"),
+		P90:     parse("90th(ms)"),
+		P95:     parse("95th(ms)"),
+		P99:     parse("99th(ms)"),
+		P999:    parse("99.9th(ms)"),
+		Max:     parse("Max(ms)"),
+	}
+	if _, ok := row["TPM"]; ok {
+		info.Ops = parse("TPM") / 60
+	}
+	if _, ok := row["Avg(s)"]; ok {
+		info.Avg = parse("Avg(s)") * 1000
+	}
+	return info
+}
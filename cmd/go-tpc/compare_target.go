@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/tpch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareHosts      []string
+	comparePorts      []int
+	compareConnParams string
+	compareUser       string
+	comparePassword   string
+	compareDBName     string
+)
+
+// registerCompareTarget registers the flags of the database to compare the query results with.
+func registerCompareTarget(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&compareHosts, "compare-host", nil, "Database host to run every query against too and compare the results with, e.g. MySQL or the previous version")
+	cmd.Flags().IntSliceVar(&comparePorts, "compare-port", nil, "Database port of --compare-host, --port by default")
+	cmd.Flags().StringVar(&compareConnParams, "compare-conn-params", "", "Connection parameters of --compare-host, --conn-params by default, e.g. tidb_isolation_read_engines='tikv' to compare TiFlash with TiKV on --host")
+	cmd.Flags().StringVar(&compareUser, "compare-user", "", "Database user of --compare-host, --user by default")
+	cmd.Flags().StringVar(&comparePassword, "compare-password", "", "Database password of --compare-host, --password by default")
+	cmd.Flags().StringVar(&compareDBName, "compare-db", "", "Database name of --compare-host, --db by default")
+}
+
+// openComparer opens the compare target, it returns nil if none of --compare-host, --compare-conn-params
+// and --compare-db is set.
+func openComparer() *tpch.Comparer {
+	if len(compareHosts) == 0 && len(compareConnParams) == 0 && len(compareDBName) == 0 {
+		return nil
+	}
+	if len(compareHosts) == 0 {
+		compareHosts = hosts
+	}
+	if len(comparePorts) == 0 {
+		comparePorts = ports
+	}
+	if len(compareConnParams) == 0 {
+		compareConnParams = connParams
+	}
+	if len(compareUser) == 0 {
+		compareUser = user
+	}
+	if len(comparePassword) == 0 {
+		comparePassword = password
+	}
+	if len(compareDBName) == 0 {
+		compareDBName = dbName
+	}
+	db, err := newDB(makeTargets(compareHosts, comparePorts), driver, compareUser, comparePassword, compareDBName, compareConnParams)
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		util.StdErrLogger.Printf("cannot connect to the compare target: %v", err)
+		os.Exit(1)
+	}
+	db.SetMaxIdleConns(threads + acThreads + 1)
+	return tpch.NewComparer(db)
+}
+
+// exitOnMismatch exits if any query result mismatched the compare target, so that a nightly run fails on wrong results.
+func exitOnMismatch(c *tpch.Comparer) {
+	if c == nil {
+		return
+	}
+	c.Close()
+	if n := c.Mismatched(); n > 0 {
+		util.StdErrLogger.Printf("%d query results mismatched the compare target", n)
+		os.Exit(1)
+	}
+}
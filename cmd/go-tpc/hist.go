@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+	"github.com/spf13/cobra"
+)
+
+var (
+	histLogPath string
+
+	histLogOnce sync.Once
+	histLogFile *os.File
+	histLog     *measurement.HistogramLogWriter
+)
+
+// trackHistLog writes every interval histogram of w to the HdrHistogram log.
+func trackHistLog(w workload.Workloader) {
+	if len(histLogPath) == 0 {
+		return
+	}
+	p, ok := w.(workload.MeasurementProvider)
+	if !ok {
+		fmt.Printf("histogram log is not supported by %s, skipped\n", w.Name())
+		return
+	}
+	histLogOnce.Do(func() {
+		var err error
+		if histLogFile, err = os.Create(histLogPath); err != nil {
+			fmt.Printf("create histogram log %s failed, err %v\n", histLogPath, err)
+			os.Exit(1)
+		}
+		if histLog, err = measurement.NewHistogramLogWriter(histLogFile, time.Now()); err != nil {
+			fmt.Printf("write histogram log %s failed, err %v\n", histLogPath, err)
+			os.Exit(1)
+		}
+	})
+	p.Measurement().AddIntervalHook(func(opMeasurement map[string]*measurement.Histogram) {
+		if err := histLog.Write(w.Name(), time.Now(), opMeasurement); err != nil {
+			fmt.Printf("write histogram log %s failed, err %v\n", histLogPath, err)
+		}
+	})
+}
+
+func closeHistLog() {
+	if histLogFile != nil {
+		histLogFile.Close()
+	}
+}
+
+func registerMergeHist(root *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "merge-hist <log>...",
+		Short: "Merge HdrHistogram logs written by --hist-log on several clients",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			executeMergeHist(args)
+		},
+	}
+	root.AddCommand(cmd)
+}
+
+func executeMergeHist(paths []string) {
+	logs := make([]io.Reader, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			util.StdErrLogger.Printf("open histogram log %s failed: %v", path, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		logs = append(logs, f)
+	}
+	merged, err := measurement.MergeHistogramLogs(logs...)
+	if err != nil {
+		util.StdErrLogger.Printf("merge histogram logs failed: %v", err)
+		os.Exit(1)
+	}
+
+	ops := make([]string, 0, len(merged))
+	for op := range merged {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	lines := [][]string{}
+	for _, op := range ops {
+		line := []string{"[Merged] ", strings.ToUpper(op)}
+		line = append(line, merged[op].GetInfo().Summary()...)
+		lines = append(lines, line)
+	}
+	headers := []string{"Prefix", "Operation", "Takes(s)", "Count", "TPM", "Sum(ms)", "Avg(ms)", "50th(ms)", "90th(ms)", "95th(ms)", "99th(ms)", "99.9th(ms)", "Max(ms)"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", headers, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+}
@@ -39,6 +39,8 @@ var (
 	driver         string
 	totalTime      time.Duration
 	totalCount     int
+	warmUpTime     time.Duration
+	rate           float64
 	dropData       bool
 	ignoreError    bool
 	outputInterval time.Duration
@@ -203,6 +205,11 @@ func main() {
 			if len(targets) == 0 {
 				targets = makeTargets(hosts, ports)
 			}
+			if err := parseLoadProfile(); err != nil {
+				fmt.Printf("invalid load profile: %v\n", err)
+				os.Exit(1)
+			}
+			initResult(cmd)
 		},
 	}
 	rootCmd.PersistentFlags().IntVar(&maxProcs, "max-procs", 0, "runtime.GOMAXPROCS")
@@ -219,6 +226,10 @@ func main() {
 	rootCmd.PersistentFlags().StringVarP(&driver, "driver", "d", mysqlDriver, "Database driver: mysql, postgres")
 	rootCmd.PersistentFlags().DurationVar(&totalTime, "time", 1<<63-1, "Total execution time")
 	rootCmd.PersistentFlags().IntVar(&totalCount, "count", 0, "Total execution count, 0 means infinite")
+	rootCmd.PersistentFlags().DurationVar(&warmUpTime, "warmup", 0, "Warm-up time at the beginning of run phase, included in --time, measurements are discarded until it ends")
+	rootCmd.PersistentFlags().StringVar(&loadProfile, "load-profile", "", "Stepped worker concurrency in run phase, e.g. 16:5m,32:5m,64:5m (threads:duration), overrides --threads")
+	rootCmd.PersistentFlags().StringVar(&loadProfileFile, "load-profile-file", "", "Path of a JSON load profile file, e.g. {\"steps\": [{\"threads\": 16, \"duration\": \"5m\"}]}")
+	rootCmd.PersistentFlags().Float64Var(&rate, "rate", 0, "Target operations per second in run phase (open loop, latency measured from the intended start time), 0 means unlimited")
 	rootCmd.PersistentFlags().BoolVar(&dropData, "dropdata", false, "Cleanup data before prepare")
 	rootCmd.PersistentFlags().BoolVar(&ignoreError, "ignore-error", false, "Ignore error when running workload")
 	rootCmd.PersistentFlags().BoolVar(&silence, "silence", false, "Don't print error when running workload")
@@ -228,6 +239,8 @@ func main() {
 5: Snapshot, 6: Serializable, 7: Linerizable`)
 	rootCmd.PersistentFlags().StringVar(&connParams, "conn-params", "", "session variables, e.g. for TiDB --conn-params tidb_isolation_read_engines='tiflash', For PostgreSQL: --conn-params sslmode=disable")
 	rootCmd.PersistentFlags().StringVar(&outputStyle, "output", util.OutputStylePlain, "output style, valid values can be { plain | table | json }")
+	rootCmd.PersistentFlags().StringVar(&resultFile, "result-file", "", "Write a JSON document with run metadata, summary, interval snapshots and workload results to this file")
+	rootCmd.PersistentFlags().StringVar(&histLogPath, "hist-log", "", "Write the latency histogram of every operation per interval to this file in HdrHistogram log format, see merge-hist")
 	rootCmd.PersistentFlags().StringSliceVar(&targets, "targets", nil, "Target database addresses")
 	rootCmd.PersistentFlags().MarkHidden("targets")
 	rootCmd.PersistentFlags().StringVar(&sslCA, "ssl-ca", "", "Path of file that contains list of trusted SSL CAs for connection")
@@ -239,8 +252,13 @@ func main() {
 	registerVersionInfo(rootCmd)
 	registerTpcc(rootCmd)
 	registerTpch(rootCmd)
+	registerSsb(rootCmd)
+	registerYcsb(rootCmd)
+	registerSysbench(rootCmd)
 	registerCHBenchmark(rootCmd)
 	registerRawsql(rootCmd)
+	registerCompare(rootCmd)
+	registerMergeHist(rootCmd)
 
 	var cancel context.CancelFunc
 	globalCtx, cancel = context.WithCancel(context.Background())
@@ -273,6 +291,7 @@ func main() {
 
 	rootCmd.Execute()
 
+	closeHistLog()
 	cancel()
 }
 
@@ -3,12 +3,40 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
+	_ "net/http/pprof"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/pingcap/go-tpc/pkg/workload"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// startHTTPServers starts the pprof and metrics endpoints if their addresses are set.
+func startHTTPServers() {
+	if pprofAddr != "" {
+		go func() {
+			if err := http.ListenAndServe(pprofAddr, http.DefaultServeMux); err != nil {
+				fmt.Printf("Failed to listen pprofAddr: %v\n", err)
+				os.Exit(1)
+			}
+		}()
+	}
+	if metricsAddr != "" {
+		go func() {
+			s := http.Server{
+				Addr:    metricsAddr,
+				Handler: promhttp.Handler(),
+			}
+			if err := s.ListenAndServe(); err != nil {
+				fmt.Printf("Failed to listen metricsAddr: %v\n", err)
+				os.Exit(1)
+			}
+		}()
+	}
+}
+
 func checkPrepare(ctx context.Context, w workload.Workloader) {
 	// skip preparation check in csv case
 	if w.Name() == "tpcc-csv" {
@@ -37,7 +65,7 @@ func checkPrepare(ctx context.Context, w workload.Workloader) {
 	wg.Wait()
 }
 
-func execute(timeoutCtx context.Context, w workload.Workloader, action string, threads, index int) error {
+func execute(timeoutCtx context.Context, w workload.Workloader, action string, threads, index int, limiter *workload.RateLimiter) error {
 	count := totalCount / threads
 
 	// For prepare, cleanup and check operations, use background context to avoid timeout constraints
@@ -67,44 +95,101 @@ func execute(timeoutCtx context.Context, w workload.Workloader, action string, t
 
 	// This loop is only reached for "run" action since other actions return earlier
 	for i := 0; i < count || count <= 0; i++ {
-		// Check if timeout has occurred before starting next query
-		select {
-		case <-ctx.Done():
-			if !silence {
+		if stop, err := executeIteration(ctx, w, action, index, i, limiter, false); stop {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// executeIteration runs the i-th iteration of the worker and returns whether the worker should stop,
+// the stop due to the end of ctx is not printed if quiet.
+func executeIteration(ctx context.Context, w workload.Workloader, action string, index, i int, limiter *workload.RateLimiter, quiet bool) (bool, error) {
+	// Check if timeout has occurred before starting next query
+	select {
+	case <-ctx.Done():
+		if !silence && !quiet {
+			fmt.Printf("[%s] %s worker %d stopped due to timeout after %d iterations\n",
+				time.Now().Format("2006-01-02 15:04:05"), action, index, i)
+		}
+		return true, nil
+	default:
+	}
+
+	runCtx := ctx
+	if limiter != nil {
+		intended, err := limiter.Wait(ctx)
+		if err != nil {
+			if !silence && !quiet {
 				fmt.Printf("[%s] %s worker %d stopped due to timeout after %d iterations\n",
 					time.Now().Format("2006-01-02 15:04:05"), action, index, i)
 			}
-			return nil
-		default:
+			return true, nil
 		}
+		runCtx = workload.WithIntendedStart(ctx, intended)
+	}
 
-		err := w.Run(ctx, index)
-		if err != nil {
-			// Check if the error is due to timeout/cancellation
-			if ctx.Err() != nil {
-				if !silence {
-					fmt.Printf("[%s] %s worker %d stopped due to timeout: %v\n",
-						time.Now().Format("2006-01-02 15:04:05"), action, index, err)
-				}
-				return nil // Don't treat timeout as an error
+	err := w.Run(runCtx, index)
+	if err != nil {
+		// Check if the error is due to timeout/cancellation
+		if ctx.Err() != nil {
+			if !silence && !quiet {
+				fmt.Printf("[%s] %s worker %d stopped due to timeout: %v\n",
+					time.Now().Format("2006-01-02 15:04:05"), action, index, err)
 			}
+			return true, nil // Don't treat timeout as an error
+		}
 
-			if !silence {
-				fmt.Printf("[%s] execute %s failed, err %v\n", time.Now().Format("2006-01-02 15:04:05"), action, err)
-			}
-			if !ignoreError {
-				return err
-			}
+		if !silence {
+			fmt.Printf("[%s] execute %s failed, err %v\n", time.Now().Format("2006-01-02 15:04:05"), action, err)
+		}
+		if !ignoreError {
+			return true, err
 		}
 	}
-
-	return nil
+	return false, nil
 }
 
 func executeWorkload(ctx context.Context, w workload.Workloader, threads int, action string) {
-	var wg sync.WaitGroup
-	wg.Add(threads)
+	executeWithHooks(ctx, w, action, func() {
+		// In open-loop mode all workers share one scheduler, so the offered load is
+		// fixed by --rate instead of by how fast the database responds.
+		var limiter *workload.RateLimiter
+		if action == "run" && rate > 0 {
+			limiter = workload.NewRateLimiter(rate)
+		}
+
+		if action == "run" && len(loadSteps) > 0 {
+			executeLoadProfile(ctx, w, limiter)
+		} else {
+			var wg sync.WaitGroup
+			wg.Add(threads)
+			for i := 0; i < threads; i++ {
+				go func(index int) {
+					defer wg.Done()
+					if err := execute(ctx, w, action, threads, index, limiter); err != nil {
+						if action == "prepare" {
+							panic(fmt.Sprintf("a fatal occurred when preparing data: %v", err))
+						}
+						fmt.Printf("execute %s failed, err %v\n", action, err)
+						return
+					}
+				}(i)
+			}
+			wg.Wait()
+		}
+
+		if action == "prepare" {
+			// For prepare, we must check the data consistency after all prepare finished
+			checkPrepare(ctx, w)
+		}
+	})
+}
 
+// executeWithHooks calls run with the periodic output of w, and for the run action with the views,
+// the plan replayer dump, the result file, the histogram log and the warm-up of w.
+func executeWithHooks(ctx context.Context, w workload.Workloader, action string, run func()) {
 	outputCtx, outputCancel := context.WithCancel(ctx)
 	ch := make(chan struct{}, 1)
 	go func() {
@@ -121,7 +206,48 @@ func executeWorkload(ctx context.Context, w workload.Workloader, threads int, ac
 			}
 		}
 	}()
-	if w.Name() == "tpch" && action == "run" {
+	if action == "run" {
+		createRunViews(w)
+	}
+	enabledDumpPlanReplayer := w.IsPlanReplayerDumpEnabled()
+	if enabledDumpPlanReplayer {
+		err := w.PreparePlanReplayerDump()
+		if err != nil {
+			fmt.Printf("[%s] prepare plan replayer failed, err%v\n",
+				time.Now().Format("2006-01-02 15:04:05"), err)
+		}
+		defer func() {
+			err = w.FinishPlanReplayerDump()
+			if err != nil {
+				fmt.Printf("[%s] dump plan replayer failed, err%v\n",
+					time.Now().Format("2006-01-02 15:04:05"), err)
+			}
+		}()
+	}
+
+	if action == "run" {
+		trackResult(w)
+		trackHistLog(w)
+	}
+	if action == "run" && warmUpTime > 0 {
+		startWarmUp(ctx, w)
+	}
+
+	run()
+
+	outputCancel()
+
+	<-ch
+
+	// Pass the last partial interval to the hooks, e.g. the result file and histogram log.
+	if p, ok := w.(workload.MeasurementProvider); ok && action == "run" {
+		p.Measurement().FlushInterval()
+	}
+}
+
+// createRunViews creates the views read by the queries of w before they are run.
+func createRunViews(w workload.Workloader) {
+	if w.Name() == "tpch" {
 		err := w.Exec(`create or replace view revenue0 (supplier_no, total_revenue) as
 	select
 		l_suppkey,
@@ -142,7 +268,7 @@ func executeWorkload(ctx context.Context, w workload.Workloader, threads int, ac
 	// However, when using CSV data ingestion, the prepare stage is skipped and
 	// the view won't exist. So we create it here when action is "run" to ensure
 	// the view is available regardless of how data was loaded.
-	if w.Name() == "ch" && action == "run" {
+	if w.Name() == "ch" {
 		err := w.Exec(`create or replace view revenue1 (supplier_no, total_revenue) as (
     select	mod((s_w_id * s_i_id),10000) as supplier_no,
               sum(ol_amount) as total_revenue
@@ -154,42 +280,24 @@ func executeWorkload(ctx context.Context, w workload.Workloader, threads int, ac
 			panic(fmt.Sprintf("a fatal occurred when preparing view data: %v", err))
 		}
 	}
-	enabledDumpPlanReplayer := w.IsPlanReplayerDumpEnabled()
-	if enabledDumpPlanReplayer {
-		err := w.PreparePlanReplayerDump()
-		if err != nil {
-			fmt.Printf("[%s] prepare plan replayer failed, err%v\n",
-				time.Now().Format("2006-01-02 15:04:05"), err)
-		}
-		defer func() {
-			err = w.FinishPlanReplayerDump()
-			if err != nil {
-				fmt.Printf("[%s] dump plan replayer failed, err%v\n",
-					time.Now().Format("2006-01-02 15:04:05"), err)
-			}
-		}()
-	}
-
-	for i := 0; i < threads; i++ {
-		go func(index int) {
-			defer wg.Done()
-			if err := execute(ctx, w, action, threads, index); err != nil {
-				if action == "prepare" {
-					panic(fmt.Sprintf("a fatal occurred when preparing data: %v", err))
-				}
-				fmt.Printf("execute %s failed, err %v\n", action, err)
-				return
-			}
-		}(i)
-	}
-
-	wg.Wait()
+}
 
-	if action == "prepare" {
-		// For prepare, we must check the data consistency after all prepare finished
-		checkPrepare(ctx, w)
+// startWarmUp discards the measurements of w until the warm-up time elapses.
+func startWarmUp(ctx context.Context, w workload.Workloader) {
+	p, ok := w.(workload.MeasurementProvider)
+	if !ok {
+		fmt.Printf("warm-up is not supported by %s, skipped\n", w.Name())
+		return
 	}
-	outputCancel()
-
-	<-ch
+	m := p.Measurement()
+	m.EnableWarmUp(true)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-time.After(warmUpTime):
+			m.FinishWarmUp()
+			fmt.Printf("[%s] %s warm-up finished after %v, start measuring\n",
+				time.Now().Format("2006-01-02 15:04:05"), w.Name(), warmUpTime)
+		}
+	}()
 }
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+)
+
+var (
+	loadProfile     string
+	loadProfileFile string
+	loadSteps       []workload.LoadStep
+)
+
+func parseLoadProfile() error {
+	var err error
+	switch {
+	case len(loadProfile) > 0 && len(loadProfileFile) > 0:
+		return fmt.Errorf("--load-profile and --load-profile-file cannot be used together")
+	case len(loadProfile) > 0:
+		loadSteps, err = workload.ParseLoadProfile(loadProfile)
+	case len(loadProfileFile) > 0:
+		loadSteps, err = workload.ReadLoadProfile(loadProfileFile)
+	}
+	return err
+}
+
+// profileStep is a step of the load profile sent to every worker, the workers whose index is
+// below threads run until ctx is done and the others stay idle.
+type profileStep struct {
+	ctx     context.Context
+	threads int
+	done    *sync.WaitGroup
+}
+
+// executeLoadProfile runs the workload step by step, each step with its own
+// number of workers, and prints a summary after every step. The workers of the
+// largest step are started once and kept across the steps, so their connections
+// and states are not reinitialized at the step boundaries.
+func executeLoadProfile(ctx context.Context, w workload.Workloader, limiter *workload.RateLimiter) {
+	var m *measurement.Measurement
+	if p, ok := w.(workload.MeasurementProvider); ok {
+		m = p.Measurement()
+		m.BeginStep()
+	}
+
+	maxThreads := 0
+	for _, step := range loadSteps {
+		if step.Threads > maxThreads {
+			maxThreads = step.Threads
+		}
+	}
+
+	// --count is the total number of iterations of all the steps, the steps end early once it is reached
+	profileCtx, stopProfile := context.WithCancel(ctx)
+	defer stopProfile()
+	var remaining *int64
+	if totalCount > 0 {
+		remaining = new(int64)
+		*remaining = int64(totalCount)
+	}
+
+	var workersWg sync.WaitGroup
+	steps := make([]chan profileStep, maxThreads)
+	workersWg.Add(maxThreads)
+	for j := range steps {
+		steps[j] = make(chan profileStep, 1)
+		go func(index int) {
+			defer workersWg.Done()
+			threadCtx := w.InitThread(ctx, index)
+			defer w.CleanupThread(threadCtx, index)
+			for step := range steps[index] {
+				if index < step.threads {
+					runProfileStep(threadCtx, step.ctx, w, index, limiter, remaining, stopProfile)
+				}
+				step.done.Done()
+			}
+		}(j)
+	}
+	defer func() {
+		for _, ch := range steps {
+			close(ch)
+		}
+		workersWg.Wait()
+	}()
+
+	for i, step := range loadSteps {
+		if profileCtx.Err() != nil {
+			return
+		}
+		fmt.Printf("[%s] load step %d/%d: %d threads for %v\n",
+			time.Now().Format("2006-01-02 15:04:05"), i+1, len(loadSteps), step.Threads, step.Duration)
+
+		stepCtx, cancel := context.WithTimeout(profileCtx, step.Duration)
+		var done sync.WaitGroup
+		done.Add(maxThreads)
+		for _, ch := range steps {
+			ch <- profileStep{ctx: stepCtx, threads: step.Threads, done: &done}
+		}
+		done.Wait()
+		cancel()
+
+		if m != nil {
+			outputStepSummary(fmt.Sprintf("[Step %d, %d threads] ", i+1, step.Threads), m.TakeStepMeasurement())
+		}
+	}
+}
+
+// runProfileStep runs the worker on its thread context until the step context is done, or the
+// remaining iterations of --count are used up, which stops the whole profile.
+func runProfileStep(threadCtx, stepCtx context.Context, w workload.Workloader, index int,
+	limiter *workload.RateLimiter, remaining *int64, stopProfile context.CancelFunc) {
+	ctx, cancel := context.WithCancel(threadCtx)
+	defer cancel()
+	stop := context.AfterFunc(stepCtx, cancel)
+	defer stop()
+
+	for i := 0; ; i++ {
+		if remaining != nil && atomic.AddInt64(remaining, -1) < 0 {
+			stopProfile()
+			return
+		}
+		if stop, err := executeIteration(ctx, w, "run", index, i, limiter, true); stop {
+			if err != nil {
+				fmt.Printf("execute run failed, err %v\n", err)
+			}
+			return
+		}
+	}
+}
+
+func outputStepSummary(prefix string, opMeasurement map[string]*measurement.Histogram) {
+	keys := make([]string, 0, len(opMeasurement))
+	for k := range opMeasurement {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := [][]string{}
+	for _, op := range keys {
+		hist := opMeasurement[op]
+		if !hist.Empty() {
+			line := []string{prefix, strings.ToUpper(op)}
+			line = append(line, hist.Summary()...)
+			lines = append(lines, line)
+		}
+	}
+	headers := []string{"Prefix", "Operation", "Takes(s)", "Count", "TPM", "Sum(ms)", "Avg(ms)", "50th(ms)", "90th(ms)", "95th(ms)", "99th(ms)", "99.9th(ms)", "Max(ms)"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", headers, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+}
@@ -63,6 +63,8 @@ func registerRawsql(root *cobra.Command) {
 		false,
 		"execute explain analyze")
 
+	registerCompareTarget(cmdRun)
+
 	cmdRun.PersistentFlags().DurationVar(&refreshConnWait, "refresh-conn-wait", 5*time.Second, "duration to wait before refreshing sql connection")
 
 	cmd.AddCommand(cmdRun)
@@ -78,6 +80,7 @@ func execRawsql(action string) {
 		util.StdErrLogger.Printf("cannot connect to the database")
 		os.Exit(1)
 	}
+	startHTTPServers()
 
 	rawsqlConfig.OutputStyle = outputStyle
 	rawsqlConfig.DBName = dbName
@@ -101,10 +104,15 @@ func execRawsql(action string) {
 	}
 
 	w := rawsql.NewWorkloader(globalDB, &rawsqlConfig)
+	if comparer := openComparer(); comparer != nil {
+		w.(*rawsql.Workloader).Comparer = comparer
+		defer exitOnMismatch(comparer)
+	}
 
 	timeoutCtx, cancel := context.WithTimeout(globalCtx, totalTime)
 	defer cancel()
 	executeWorkload(timeoutCtx, w, threads, action)
 	fmt.Println("Finished")
 	w.OutputStats(true)
+	writeResult(w)
 }
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/workload"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	resultFile string
+
+	runResult *result
+)
+
+// result is the machine-readable document written to --result-file for every run.
+type result struct {
+	mu sync.Mutex
+
+	Workload  string            `json:"workload"`
+	Action    string            `json:"action"`
+	Command   string            `json:"command"`
+	Flags     map[string]string `json:"flags"`
+	DBVersion string            `json:"db_version,omitempty"`
+	StartTime time.Time         `json:"start_time"`
+	EndTime   time.Time         `json:"end_time"`
+	Version   versionResult     `json:"version"`
+	Workloads []*workloadResult `json:"workloads"`
+}
+
+type versionResult struct {
+	Release   string `json:"release"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+}
+
+type workloadResult struct {
+	Name      string                          `json:"name"`
+	Summary   map[string]measurement.HistInfo `json:"summary"`
+	Intervals []intervalResult                `json:"intervals"`
+	Results   map[string]float64              `json:"results,omitempty"`
+}
+
+type intervalResult struct {
+	Time       time.Time                       `json:"time"`
+	Operations map[string]measurement.HistInfo `json:"operations"`
+}
+
+const redactedFlagValue = "<redacted>"
+
+// initResult starts recording the run of cmd if --result-file is set.
+func initResult(cmd *cobra.Command) {
+	if len(resultFile) == 0 {
+		return
+	}
+	runResult = &result{
+		Command:   cmd.CommandPath(),
+		Flags:     make(map[string]string),
+		StartTime: time.Now(),
+		Version: versionResult{
+			Release:   version,
+			GitCommit: commit,
+			BuildTime: date,
+		},
+	}
+	if cmd.Parent() != nil {
+		runResult.Workload = cmd.Parent().Name()
+		runResult.Action = cmd.Name()
+	}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if strings.HasSuffix(f.Name, "password") || f.Name == "help" {
+			return
+		}
+		value := f.Value.String()
+		// the connection parameters may carry credentials, e.g. password=... of PostgreSQL
+		if strings.HasSuffix(f.Name, "conn-params") && len(value) > 0 {
+			value = redactedFlagValue
+		}
+		runResult.Flags[f.Name] = value
+	})
+}
+
+// trackResult records the interval measurements of w into the result.
+func trackResult(w workload.Workloader) {
+	if runResult == nil {
+		return
+	}
+	wr := &workloadResult{Name: w.Name()}
+	runResult.mu.Lock()
+	runResult.Workloads = append(runResult.Workloads, wr)
+	runResult.mu.Unlock()
+
+	if p, ok := w.(workload.MeasurementProvider); ok {
+		p.Measurement().AddIntervalHook(func(opMeasurement map[string]*measurement.Histogram) {
+			interval := intervalResult{Time: time.Now(), Operations: histInfos(opMeasurement)}
+			runResult.mu.Lock()
+			wr.Intervals = append(wr.Intervals, interval)
+			runResult.mu.Unlock()
+		})
+	}
+}
+
+// writeResult writes the summary of workloads to the result file.
+func writeResult(workloads ...workload.Workloader) {
+	if runResult == nil {
+		return
+	}
+	runResult.mu.Lock()
+	defer runResult.mu.Unlock()
+
+	runResult.EndTime = time.Now()
+	if globalDB != nil {
+		if ver, err := getServerVersion(globalDB); err == nil {
+			runResult.DBVersion = ver
+		}
+	}
+	for _, w := range workloads {
+		var wr *workloadResult
+		for _, r := range runResult.Workloads {
+			if r.Name == w.Name() {
+				wr = r
+			}
+		}
+		if wr == nil {
+			wr = &workloadResult{Name: w.Name()}
+			runResult.Workloads = append(runResult.Workloads, wr)
+		}
+		if p, ok := w.(workload.MeasurementProvider); ok {
+			m := p.Measurement()
+			m.RLock()
+			wr.Summary = histInfos(m.OpSumMeasurement)
+			m.RUnlock()
+		}
+		if p, ok := w.(workload.ResultProvider); ok {
+			wr.Results = p.Results()
+		}
+	}
+
+	data, err := json.MarshalIndent(runResult, "", "  ")
+	if err != nil {
+		fmt.Printf("marshal result failed, err %v\n", err)
+		return
+	}
+	if err := os.WriteFile(resultFile, data, 0644); err != nil {
+		fmt.Printf("write result file %s failed, err %v\n", resultFile, err)
+	}
+}
+
+func histInfos(opMeasurement map[string]*measurement.Histogram) map[string]measurement.HistInfo {
+	infos := make(map[string]measurement.HistInfo, len(opMeasurement))
+	for op, hist := range opMeasurement {
+		if !hist.Empty() {
+			infos[op] = hist.GetInfo()
+		}
+	}
+	return infos
+}
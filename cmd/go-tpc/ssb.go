@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/ssb"
+	"github.com/spf13/cobra"
+)
+
+var ssbConfig ssb.Config
+
+func executeSsb(action string) {
+	if sf := ssbConfig.ScaleFactor; sf < 0.001 || (sf > 1 && sf != math.Trunc(sf)) {
+		util.StdErrLogger.Printf("invalid scale factor %g, it should be an integer or a fraction in [0.001, 1)", sf)
+		os.Exit(1)
+	}
+	openDB()
+	defer closeDB()
+
+	if globalDB == nil {
+		util.StdErrLogger.Printf("cannot connect to the database")
+		os.Exit(1)
+	}
+	startHTTPServers()
+	if maxProcs != 0 {
+		runtime.GOMAXPROCS(maxProcs)
+	}
+
+	ssbConfig.OutputStyle = outputStyle
+	ssbConfig.Driver = driver
+	ssbConfig.DBName = dbName
+	ssbConfig.PrepareThreads = threads
+	ssbConfig.QueryNames = strings.Split(ssbConfig.RawQueries, ",")
+	w := ssb.NewWorkloader(globalDB, &ssbConfig)
+	timeoutCtx, cancel := context.WithTimeout(globalCtx, totalTime)
+	defer cancel()
+
+	executeWorkload(timeoutCtx, w, threads, action)
+	fmt.Println("Finished")
+	w.OutputStats(true)
+	writeResult(w)
+}
+
+func registerSsb(root *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "ssb",
+		Short: "Star Schema Benchmark with the data derived from TPC-H dbgen",
+	}
+
+	cmd.PersistentFlags().StringVar(&ssbConfig.RawQueries,
+		"queries",
+		"q1.1,q1.2,q1.3,q2.1,q2.2,q2.3,q3.1,q3.2,q3.3,q3.4,q4.1,q4.2,q4.3",
+		"All queries")
+
+	cmd.PersistentFlags().Float64Var(&ssbConfig.ScaleFactor,
+		"sf",
+		1,
+		"scale factor, e.g. 0.01 for a smoke test, a fractional one must be less than 1")
+
+	var cmdPrepare = &cobra.Command{
+		Use:   "prepare",
+		Short: "Prepare data for the workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeSsb("prepare")
+		},
+	}
+
+	cmdPrepare.PersistentFlags().IntVar(&ssbConfig.TiFlashReplica,
+		"tiflash-replica",
+		0,
+		"Number of tiflash replica")
+	cmdPrepare.PersistentFlags().StringVar(&ssbConfig.OutputType,
+		"output-type",
+		"",
+		"Output file type. If empty, then load data to db. Current only support csv")
+	cmdPrepare.PersistentFlags().StringVar(&ssbConfig.OutputDir,
+		"output-dir",
+		"",
+		"Output directory for generating file if specified")
+
+	var cmdRun = &cobra.Command{
+		Use:   "run",
+		Short: "Run workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeSsb("run")
+		},
+	}
+
+	var cmdCleanup = &cobra.Command{
+		Use:   "cleanup",
+		Short: "Cleanup data for the workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeSsb("cleanup")
+		},
+	}
+
+	cmd.AddCommand(cmdRun, cmdPrepare, cmdCleanup)
+	root.AddCommand(cmd)
+}
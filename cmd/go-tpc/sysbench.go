@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/sysbench"
+	"github.com/spf13/cobra"
+)
+
+var sysbenchConfig sysbench.Config
+
+func executeSysbench(action string) {
+	openDB()
+	defer closeDB()
+
+	if globalDB == nil {
+		util.StdErrLogger.Printf("cannot connect to the database")
+		os.Exit(1)
+	}
+	startHTTPServers()
+	if maxProcs != 0 {
+		runtime.GOMAXPROCS(maxProcs)
+	}
+
+	sysbenchConfig.OutputStyle = outputStyle
+	sysbenchConfig.Driver = driver
+	sysbenchConfig.DBName = dbName
+	sysbenchConfig.Threads = threads
+	w, err := sysbench.NewWorkloader(globalDB, &sysbenchConfig)
+	if err != nil {
+		fmt.Printf("Failed to init work loader: %v\n", err)
+		os.Exit(1)
+	}
+	timeoutCtx, cancel := context.WithTimeout(globalCtx, totalTime)
+	defer cancel()
+
+	executeWorkload(timeoutCtx, w, threads, action)
+	fmt.Println("Finished")
+	w.OutputStats(true)
+	writeResult(w)
+}
+
+func registerSysbench(root *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "sysbench",
+		Short: "Sysbench OLTP workloads on the sbtest tables",
+	}
+
+	cmd.PersistentFlags().StringVar(&sysbenchConfig.Workload, "workload", "oltp_read_write", "Workload: oltp_read_only, oltp_read_write, oltp_write_only, oltp_point_select, oltp_update_index, oltp_insert")
+	cmd.PersistentFlags().IntVar(&sysbenchConfig.Tables, "tables", 1, "Number of tables")
+	cmd.PersistentFlags().IntVar(&sysbenchConfig.TableSize, "table-size", 10000, "Number of rows per table")
+	cmd.PersistentFlags().IntVar(&sysbenchConfig.RangeSize, "range-size", 100, "Range size for range SELECT queries")
+	cmd.PersistentFlags().BoolVar(&sysbenchConfig.AutoInc, "auto-inc", true, "Use AUTO_INCREMENT column as Primary Key, the ids of oltp_insert are assigned by the database")
+
+	var cmdPrepare = &cobra.Command{
+		Use:   "prepare",
+		Short: "Prepare data for the workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeSysbench("prepare")
+		},
+	}
+	cmdPrepare.PersistentFlags().BoolVar(&sysbenchConfig.Secondary, "secondary", false, "Use a secondary index in place of the PRIMARY KEY")
+	cmdPrepare.PersistentFlags().BoolVar(&sysbenchConfig.CreateSecondary, "create-secondary", true, "Create a secondary index on k in addition to the PRIMARY KEY")
+
+	var cmdRun = &cobra.Command{
+		Use:   "run",
+		Short: "Run workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeSysbench("run")
+		},
+	}
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.PointSelects, "point-selects", 10, "Number of point SELECT queries per transaction")
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.SimpleRanges, "simple-ranges", 1, "Number of simple range SELECT queries per transaction")
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.SumRanges, "sum-ranges", 1, "Number of SELECT SUM() queries per transaction")
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.OrderRanges, "order-ranges", 1, "Number of SELECT ORDER BY queries per transaction")
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.DistinctRanges, "distinct-ranges", 1, "Number of SELECT DISTINCT queries per transaction")
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.IndexUpdates, "index-updates", 1, "Number of UPDATE index queries per transaction")
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.NonIndexUpdates, "non-index-updates", 1, "Number of UPDATE non-index queries per transaction")
+	cmdRun.PersistentFlags().IntVar(&sysbenchConfig.DeleteInserts, "delete-inserts", 1, "Number of DELETE/INSERT combinations per transaction")
+	cmdRun.PersistentFlags().BoolVar(&sysbenchConfig.RangeSelects, "range-selects", true, "Enable/disable all range SELECT queries")
+	cmdRun.PersistentFlags().BoolVar(&sysbenchConfig.SkipTrx, "skip-trx", false, "Don't start explicit transactions and execute all queries in the AUTOCOMMIT mode")
+	cmdRun.PersistentFlags().DurationVar(&sysbenchConfig.MaxMeasureLatency, "max-measure-latency", measurement.DefaultMaxLatency, "max measure latency in millisecond")
+
+	var cmdCleanup = &cobra.Command{
+		Use:   "cleanup",
+		Short: "Cleanup data for the workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeSysbench("cleanup")
+		},
+	}
+
+	cmd.AddCommand(cmdRun, cmdPrepare, cmdCleanup)
+	root.AddCommand(cmd)
+}
@@ -3,8 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"net/http"
-	_ "net/http/pprof"
 	"os"
 	"runtime"
 	"time"
@@ -12,37 +10,26 @@ import (
 	"github.com/pingcap/go-tpc/pkg/measurement"
 	"github.com/pingcap/go-tpc/pkg/workload"
 	"github.com/pingcap/go-tpc/tpcc"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 )
 
 var tpccConfig tpcc.Config
 
 func executeTpcc(action string) {
-	if pprofAddr != "" {
-		go func() {
-			if err := http.ListenAndServe(pprofAddr, http.DefaultServeMux); err != nil {
-				fmt.Printf("Failed to listen pprofAddr: %v\n", err)
-				os.Exit(1)
-			}
-		}()
-	}
-	if metricsAddr != "" {
-		go func() {
-			s := http.Server{
-				Addr:    metricsAddr,
-				Handler: promhttp.Handler(),
-			}
-			if err := s.ListenAndServe(); err != nil {
-				fmt.Printf("Failed to listen metricsAddr: %v\n", err)
-				os.Exit(1)
-			}
-		}()
-	}
+	startHTTPServers()
 	if maxProcs != 0 {
 		runtime.GOMAXPROCS(maxProcs)
 	}
 
+	if action == "run" && rate > 0 && tpccConfig.Wait {
+		fmt.Println("--rate cannot be used together with --wait")
+		os.Exit(1)
+	}
+	if action == "run" && tpccConfig.Terminals && len(loadSteps) > 0 {
+		fmt.Println("--terminals cannot be used together with a load profile, the terminals are fixed by --threads")
+		os.Exit(1)
+	}
+
 	openDB()
 	defer closeDB()
 
@@ -81,10 +68,34 @@ func executeTpcc(action string) {
 	timeoutCtx, cancel := context.WithTimeout(globalCtx, totalTime)
 	defer cancel()
 
+	if action == "acid" {
+		executeTpccACID(timeoutCtx, w)
+		return
+	}
+
 	executeWorkload(timeoutCtx, w, threads, action)
 
 	fmt.Println("Finished")
 	w.OutputStats(true)
+	writeResult(w)
+}
+
+func executeTpccACID(ctx context.Context, w workload.Workloader) {
+	tw, ok := w.(*tpcc.Workloader)
+	if !ok {
+		fmt.Println("ACID tests can only run against a database")
+		os.Exit(1)
+	}
+	passed, err := tw.RunACID(ctx)
+	if err != nil {
+		fmt.Printf("Failed to run ACID tests: %v\n", err)
+		os.Exit(1)
+	}
+	if !passed {
+		fmt.Println("ACID tests failed")
+		os.Exit(1)
+	}
+	fmt.Println("Finished")
 }
 
 func registerTpcc(root *cobra.Command) {
@@ -122,8 +133,16 @@ func registerTpcc(root *cobra.Command) {
 		},
 	}
 	cmdRun.PersistentFlags().BoolVar(&tpccConfig.Wait, "wait", false, "including keying & thinking time described on TPC-C Standard Specification")
+	cmdRun.PersistentFlags().IntVar(&tpccConfig.DeliveryWorkers, "delivery-workers", 0, "Number of workers executing the Delivery transactions queued by the threads in deferred mode, 0 means executing them in the threads")
+	cmdRun.PersistentFlags().StringVar(&tpccConfig.DeliveryResultFile, "delivery-result-file", "", "Write the queued and completed time and the delivered orders of every deferred Delivery to this file")
+	cmdRun.PersistentFlags().BoolVar(&tpccConfig.Terminals, "terminals", false, "Bind every thread to a home warehouse and district like a terminal of TPC-C Standard Specification, --threads must be 10 per warehouse")
+	cmdRun.PersistentFlags().DurationVar(&tpccConfig.CheckInterval, "check-interval", 0, "Check the consistency of a random warehouse in a read-only snapshot at this interval in the background during run, 0 means disabled")
+	cmdRun.PersistentFlags().StringSliceVar(&tpccConfig.CheckConditions, "check-conditions", tpcc.DefaultOnlineCheckConditions, "Consistency conditions checked in the background during run, from 3.3.2.1 to 3.3.2.12 except 3.3.2.11")
 	cmdRun.PersistentFlags().DurationVar(&tpccConfig.MaxMeasureLatency, "max-measure-latency", measurement.DefaultMaxLatency, "max measure latency in millisecond")
 	cmdRun.PersistentFlags().IntSliceVar(&tpccConfig.Weight, "weight", []int{45, 43, 4, 4, 4}, "Weight for NewOrder, Payment, OrderStatus, Delivery, StockLevel")
+	cmdRun.PersistentFlags().IntVar(&tpccConfig.TxnRetryMaxAttempts, "txn-retry-max-attempts", 1, "Max attempts of a transaction, 1 means no retry")
+	cmdRun.PersistentFlags().DurationVar(&tpccConfig.TxnRetryBackoff, "txn-retry-backoff", 10*time.Millisecond, "Backoff before the first retry, doubled after every retry")
+	cmdRun.PersistentFlags().StringSliceVar(&tpccConfig.TxnRetryErrorClasses, "txn-retry-error-classes", []string{"conflict"}, "Error classes to retry, from conflict, timeout, connection and other")
 	cmdRun.Flags().DurationVar(&tpccConfig.ConnRefreshInterval, "conn-refresh-interval", 0, "automatically refresh database connections at specified intervals to balance traffic across new replicas (0 = disabled, e.g., 10s)")
 
 	var cmdCleanup = &cobra.Command{
@@ -142,7 +161,18 @@ func registerTpcc(root *cobra.Command) {
 		},
 	}
 
-	cmd.AddCommand(cmdRun, cmdPrepare, cmdCleanup, cmdCheck)
+	var cmdACID = &cobra.Command{
+		Use:   "acid",
+		Short: "Run the atomicity, isolation and durability tests of TPC-C Standard Specification",
+		Run: func(cmd *cobra.Command, _ []string) {
+			executeTpcc("acid")
+		},
+	}
+	cmdACID.PersistentFlags().StringSliceVar(&tpccConfig.ACIDTests, "tests", nil, "ACID tests to run, e.g. atomicity, isolation-3 or durability, all tests by default")
+	cmdACID.PersistentFlags().DurationVar(&tpccConfig.ACIDBlockWait, "block-wait", tpcc.DefaultACIDBlockWait, "Time to wait before a transaction is regarded as blocked by the other one in the isolation tests")
+	cmdACID.PersistentFlags().DurationVar(&tpccConfig.ACIDDurabilityPause, "durability-pause", 0, "Pause between committing and verifying the transactions of the durability test, to inject a failure like killing the database")
+
+	cmd.AddCommand(cmdRun, cmdPrepare, cmdCleanup, cmdCheck, cmdACID)
 
 	root.AddCommand(cmd)
 }
@@ -4,16 +4,25 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/pingcap/go-tpc/pkg/util"
 	"github.com/pingcap/go-tpc/tpch"
 	"github.com/spf13/cobra"
 )
 
-var tpchConfig tpch.Config
+var (
+	tpchConfig  tpch.Config
+	tpchQphH    bool
+	tpchStreams int
+	tpchChunk   string
+
+	tpchAnswerDir string
+)
 
 var queryTuningVars = []struct {
 	name  string
@@ -34,6 +43,15 @@ func isSysVarSupported(ver util.SemVersion, sysVar string) bool {
 }
 
 func executeTpch(action string) {
+	if sf := tpchConfig.ScaleFactor; sf < 0.001 || (sf > 1 && sf != math.Trunc(sf)) {
+		util.StdErrLogger.Printf("invalid scale factor %g, it should be an integer or a fraction in [0.001, 1)", sf)
+		os.Exit(1)
+	}
+	if _, err := fmt.Sscanf(tpchChunk, "%d/%d", &tpchConfig.Chunk, &tpchConfig.Chunks); err != nil ||
+		tpchConfig.Chunk < 1 || tpchConfig.Chunk > tpchConfig.Chunks {
+		util.StdErrLogger.Printf("invalid chunk %q, it should be i/n with 1 <= i <= n", tpchChunk)
+		os.Exit(1)
+	}
 	openDB()
 	defer closeDB()
 
@@ -41,6 +59,7 @@ func executeTpch(action string) {
 		util.StdErrLogger.Printf("cannot connect to the database")
 		os.Exit(1)
 	}
+	startHTTPServers()
 	if maxProcs != 0 {
 		runtime.GOMAXPROCS(maxProcs)
 	}
@@ -70,13 +89,60 @@ func executeTpch(action string) {
 	tpchConfig.DBName = dbName
 	tpchConfig.PrepareThreads = threads
 	tpchConfig.QueryNames = strings.Split(tpchConfig.RawQueries, ",")
+	if action == "run" && tpchQphH && !tpchConfig.EnableQGen {
+		// the spec requires the parameters generated by qgen for every query stream
+		fmt.Println("Enabling qgen for the query streams of QphH")
+		tpchConfig.EnableQGen = true
+	}
+	if action == "run" && tpchConfig.EnableQGen {
+		if tpchConfig.QGenSeed == 0 {
+			tpchConfig.QGenSeed = time.Now().UnixNano()
+		}
+		fmt.Printf("Using qgen seed %d\n", tpchConfig.QGenSeed)
+		if tpchConfig.EnableOutputCheck {
+			fmt.Println("Output check is skipped since the answers are only for the default query parameters")
+		}
+	} else if action == "run" && tpchConfig.EnableOutputCheck && len(tpchAnswerDir) == 0 {
+		if err := tpch.CheckAnswerSet(tpchConfig.ScaleFactor, driver); err != nil {
+			util.StdErrLogger.Printf("cannot check output: %v, use --answer-dir to check against external answers", err)
+			os.Exit(1)
+		}
+	}
 	w := tpch.NewWorkloader(globalDB, &tpchConfig)
+	if action == "run" {
+		if comparer := openComparer(); comparer != nil {
+			for _, queryName := range tpchConfig.QueryNames {
+				if tpchQphH || queryName == "rf1" || queryName == "rf2" {
+					util.StdErrLogger.Printf("cannot compare with the refresh functions which only modify the tested database")
+					os.Exit(1)
+				}
+			}
+			w.(*tpch.Workloader).Comparer = comparer
+			defer exitOnMismatch(comparer)
+		}
+	}
+	if action == "run" && tpchConfig.EnableOutputCheck && len(tpchAnswerDir) > 0 {
+		if err := w.(*tpch.Workloader).LoadAnswers(tpchAnswerDir); err != nil {
+			util.StdErrLogger.Printf("load answers failed: %v", err)
+			os.Exit(1)
+		}
+	}
 	timeoutCtx, cancel := context.WithTimeout(globalCtx, totalTime)
 	defer cancel()
 
-	executeWorkload(timeoutCtx, w, threads, action)
+	if action == "run" && tpchQphH {
+		executeWithHooks(timeoutCtx, w, action, func() {
+			if _, err := w.(*tpch.Workloader).RunQphH(timeoutCtx, tpchStreams); err != nil {
+				util.StdErrLogger.Printf("run QphH failed: %v", err)
+				os.Exit(1)
+			}
+		})
+	} else {
+		executeWorkload(timeoutCtx, w, threads, action)
+	}
 	fmt.Println("Finished")
 	w.OutputStats(true)
+	writeResult(w)
 }
 
 func getServerVersion(db *sql.DB) (string, error) {
@@ -106,12 +172,12 @@ func registerTpch(root *cobra.Command) {
 	cmd.PersistentFlags().StringVar(&tpchConfig.RawQueries,
 		"queries",
 		"q1,q2,q3,q4,q5,q6,q7,q8,q9,q10,q11,q12,q13,q14,q15,q16,q17,q18,q19,q20,q21,q22",
-		"All queries")
+		"All queries, rf1 and rf2 are the new sales and old sales refresh functions")
 
-	cmd.PersistentFlags().IntVar(&tpchConfig.ScaleFactor,
+	cmd.PersistentFlags().Float64Var(&tpchConfig.ScaleFactor,
 		"sf",
 		1,
-		"scale factor")
+		"scale factor, e.g. 0.01 for a smoke test, a fractional one must be less than 1")
 
 	cmd.PersistentFlags().BoolVar(&tpchConfig.ExecExplainAnalyze,
 		"use-explain",
@@ -121,7 +187,7 @@ func registerTpch(root *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&tpchConfig.EnableOutputCheck,
 		"check",
 		false,
-		"Check output data, only when the scale factor equals 1")
+		"Check output data against the answer set of the scale factor")
 
 	var cmdPrepare = &cobra.Command{
 		Use:   "prepare",
@@ -153,6 +219,10 @@ func registerTpch(root *cobra.Command) {
 		"tidb_index_serial_scan_concurrency",
 		1,
 		"tidb_index_serial_scan_concurrency param for analyze jobs")
+	cmdPrepare.PersistentFlags().StringVar(&tpchChunk,
+		"chunk",
+		"1/1",
+		"Only generate the i-th of n chunks of the data like dbgen -C n -S i, to prepare on n clients concurrently")
 	cmdPrepare.PersistentFlags().StringVar(&tpchConfig.OutputType,
 		"output-type",
 		"",
@@ -185,6 +255,38 @@ func registerTpch(root *cobra.Command) {
 		"",
 		"Name of plan Replayer file dumps")
 
+	cmdRun.PersistentFlags().BoolVar(&tpchQphH,
+		"qphh",
+		false,
+		"Run the power test and the throughput test of the spec and compute QphH@Size, --queries is ignored and --qgen is enabled")
+
+	cmdRun.PersistentFlags().IntVar(&tpchStreams,
+		"streams",
+		0,
+		"Number of query streams in the throughput test, 0 means the minimum required by the spec for the scale factor")
+
+	cmdRun.PersistentFlags().Int64Var(&tpchConfig.RefreshStartSet,
+		"refresh-start-set",
+		1,
+		"The first refresh set used by rf1 and rf2, a later run should start from the next set of the last run")
+
+	cmdRun.PersistentFlags().BoolVar(&tpchConfig.EnableQGen,
+		"qgen",
+		false,
+		"Substitute the query parameters generated by qgen for every query stream instead of the fixed ones")
+
+	cmdRun.PersistentFlags().Int64Var(&tpchConfig.QGenSeed,
+		"qgen-seed",
+		0,
+		"The seed of qgen to reproduce the query parameters of a run, 0 means a random seed")
+
+	cmdRun.PersistentFlags().StringVar(&tpchAnswerDir,
+		"answer-dir",
+		"",
+		"Directory of the answer files like q1.out of dbgen to check the output against with --check, for any scale factor")
+
+	registerCompareTarget(cmdRun)
+
 	cmdRun.PersistentFlags().BoolVar(&tpchConfig.EnableQueryTuning,
 		"enable-query-tuning",
 		true,
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/ycsb"
+	"github.com/spf13/cobra"
+)
+
+var ycsbConfig ycsb.Config
+
+func executeYcsb(action string) {
+	openDB()
+	defer closeDB()
+
+	if globalDB == nil {
+		util.StdErrLogger.Printf("cannot connect to the database")
+		os.Exit(1)
+	}
+	startHTTPServers()
+	if maxProcs != 0 {
+		runtime.GOMAXPROCS(maxProcs)
+	}
+
+	ycsbConfig.OutputStyle = outputStyle
+	ycsbConfig.Driver = driver
+	ycsbConfig.DBName = dbName
+	ycsbConfig.Threads = threads
+	w, err := ycsb.NewWorkloader(globalDB, &ycsbConfig)
+	if err != nil {
+		fmt.Printf("Failed to init work loader: %v\n", err)
+		os.Exit(1)
+	}
+	timeoutCtx, cancel := context.WithTimeout(globalCtx, totalTime)
+	defer cancel()
+
+	executeWorkload(timeoutCtx, w, threads, action)
+	fmt.Println("Finished")
+	w.OutputStats(true)
+	writeResult(w)
+}
+
+func registerYcsb(root *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "ycsb",
+		Short: "YCSB core workloads A-F on the usertable",
+	}
+
+	cmd.PersistentFlags().Int64Var(&ycsbConfig.RecordCount, "record-count", 1000000, "Number of records loaded in the usertable")
+	cmd.PersistentFlags().IntVar(&ycsbConfig.FieldCount, "field-count", 10, "Number of fields of a record")
+	cmd.PersistentFlags().IntVar(&ycsbConfig.FieldLength, "field-length", 100, "Length of a field")
+	cmd.PersistentFlags().StringVar(&ycsbConfig.Workload, "workload", "a", "Core workload: a (update heavy), b (read mostly), c (read only), d (read latest), e (short ranges), f (read-modify-write)")
+	cmd.PersistentFlags().IntVar(&ycsbConfig.MaxScanLength, "max-scan-length", 100, "Max number of records of a scan, the length is uniform in [1, max-scan-length]")
+
+	var cmdPrepare = &cobra.Command{
+		Use:   "prepare",
+		Short: "Prepare data for the workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeYcsb("prepare")
+		},
+	}
+
+	var cmdRun = &cobra.Command{
+		Use:   "run",
+		Short: "Run workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeYcsb("run")
+		},
+	}
+	cmdRun.PersistentFlags().StringVar(&ycsbConfig.RequestDistribution, "request-distribution", "", "Distribution of the requested keys: uniform, zipfian, latest, hotspot, default is the one of the workload")
+	cmdRun.PersistentFlags().Float64Var(&ycsbConfig.HotspotDataFraction, "hotspot-data-fraction", 0.2, "Fraction of the keys in the hot set of the hotspot distribution")
+	cmdRun.PersistentFlags().Float64Var(&ycsbConfig.HotspotOpnFraction, "hotspot-opn-fraction", 0.8, "Fraction of the operations accessing the hot set of the hotspot distribution")
+	cmdRun.PersistentFlags().DurationVar(&ycsbConfig.MaxMeasureLatency, "max-measure-latency", measurement.DefaultMaxLatency, "max measure latency in millisecond")
+
+	var cmdCleanup = &cobra.Command{
+		Use:   "cleanup",
+		Short: "Cleanup data for the workload",
+		Run: func(cmd *cobra.Command, args []string) {
+			executeYcsb("cleanup")
+		},
+	}
+
+	cmd.AddCommand(cmdRun, cmdPrepare, cmdCleanup)
+	root.AddCommand(cmd)
+}
@@ -0,0 +1,66 @@
+package measurement
+
+import (
+	"fmt"
+	"math"
+)
+
+// Delta is the relative change of an operation from a baseline run to a candidate run.
+type Delta struct {
+	Op        string
+	Baseline  HistInfo
+	Candidate HistInfo
+
+	// Changes in percent, positive means the candidate is higher.
+	Ops float64
+	Avg float64
+	P50 float64
+	P95 float64
+	P99 float64
+}
+
+// Thresholds are the allowed changes in percent before a Delta is a regression.
+type Thresholds struct {
+	MaxOpsDrop         float64
+	MaxLatencyIncrease float64
+}
+
+// Compare computes the relative change of an operation between two runs.
+func Compare(op string, baseline, candidate HistInfo) Delta {
+	return Delta{
+		Op:        op,
+		Baseline:  baseline,
+		Candidate: candidate,
+		Ops:       change(baseline.Ops, candidate.Ops),
+		Avg:       change(baseline.Avg, candidate.Avg),
+		P50:       change(baseline.P50, candidate.P50),
+		P95:       change(baseline.P95, candidate.P95),
+		P99:       change(baseline.P99, candidate.P99),
+	}
+}
+
+// Regressions returns the reasons why d exceeds the thresholds, or nil if it does not.
+func (d Delta) Regressions(t Thresholds) []string {
+	var reasons []string
+	if d.Ops < -t.MaxOpsDrop {
+		reasons = append(reasons, fmt.Sprintf("throughput dropped %.1f%%", -d.Ops))
+	}
+	for _, l := range []struct {
+		name   string
+		change float64
+	}{{"avg", d.Avg}, {"p50", d.P50}, {"p95", d.P95}, {"p99", d.P99}} {
+		if l.change > t.MaxLatencyIncrease {
+			reasons = append(reasons, fmt.Sprintf("%s latency increased %.1f%%", l.name, l.change))
+		}
+	}
+	return reasons
+}
+
+// change returns the change from base to cand in percent, or 0 if either is missing. A candidate
+// of 0 is a change of -100% from a non-zero base, e.g. the throughput collapsed.
+func change(base, cand float64) float64 {
+	if base == 0 || math.IsNaN(base) || math.IsNaN(cand) {
+		return 0
+	}
+	return (cand - base) / base * 100
+}
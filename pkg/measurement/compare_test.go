@@ -0,0 +1,35 @@
+package measurement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	base := HistInfo{Ops: 100, Avg: 10, P50: 8, P95: 20, P99: 40}
+	cand := HistInfo{Ops: 90, Avg: 10.5, P50: 8, P95: 30, P99: 0}
+	d := Compare("new_order", base, cand)
+	assert.InDelta(t, -10, d.Ops, 1e-9)
+	assert.InDelta(t, 5, d.Avg, 1e-9)
+	assert.InDelta(t, 0, d.P50, 1e-9)
+	assert.InDelta(t, 50, d.P95, 1e-9)
+	assert.InDelta(t, -100, d.P99, 1e-9)
+
+	assert.Equal(t, []string{"throughput dropped 10.0%", "p95 latency increased 50.0%"},
+		d.Regressions(Thresholds{MaxOpsDrop: 5, MaxLatencyIncrease: 10}))
+	assert.Empty(t, d.Regressions(Thresholds{MaxOpsDrop: 20, MaxLatencyIncrease: 60}))
+}
+
+func TestCompareZero(t *testing.T) {
+	// a collapsed throughput is a 100% drop
+	d := Compare("TPM", HistInfo{Ops: 100}, HistInfo{Ops: 0})
+	assert.InDelta(t, -100, d.Ops, 1e-9)
+	assert.Equal(t, []string{"throughput dropped 100.0%"}, d.Regressions(Thresholds{MaxOpsDrop: 5, MaxLatencyIncrease: 10}))
+
+	// nothing is compared with a missing baseline
+	d = Compare("TPM", HistInfo{Ops: 0}, HistInfo{Ops: 100, Avg: 10})
+	assert.InDelta(t, 0, d.Ops, 1e-9)
+	assert.InDelta(t, 0, d.Avg, 1e-9)
+	assert.Empty(t, d.Regressions(Thresholds{MaxOpsDrop: 5, MaxLatencyIncrease: 10}))
+}
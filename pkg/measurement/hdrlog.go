@@ -0,0 +1,121 @@
+package measurement
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// HistogramLogWriter writes histograms in the HdrHistogram interval log format,
+// every line is tagged with the operation name.
+type HistogramLogWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	// the end of the last interval written by every source, the next interval starts from it
+	lastEnds map[string]time.Time
+}
+
+// NewHistogramLogWriter creates a HistogramLogWriter and writes the log header.
+func NewHistogramLogWriter(w io.Writer, start time.Time) (*HistogramLogWriter, error) {
+	lw := hdrhistogram.NewHistogramLogWriter(w)
+	if err := lw.OutputLogFormatVersion(); err != nil {
+		return nil, err
+	}
+	if err := lw.OutputStartTime(start.UnixNano() / int64(time.Millisecond)); err != nil {
+		return nil, err
+	}
+	return &HistogramLogWriter{w: w, start: start, lastEnds: make(map[string]time.Time)}, nil
+}
+
+// Write writes one interval line for every non-empty histogram in opMeasurement of the source,
+// e.g. a workload sharing the log with others. The interval is from the end of the last interval
+// of the source, or the start of the log, to end, so the idle time is included.
+func (l *HistogramLogWriter) Write(source string, end time.Time, opMeasurement map[string]*Histogram) error {
+	ops := make([]string, 0, len(opMeasurement))
+	for op := range opMeasurement {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	start, ok := l.lastEnds[source]
+	if !ok {
+		start = l.start
+	}
+	l.lastEnds[source] = end
+	for _, op := range ops {
+		hist := opMeasurement[op]
+		if hist.Empty() {
+			continue
+		}
+		h := hist.snapshot()
+		payload, err := h.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+		if err != nil {
+			return err
+		}
+		// Timestamps are in seconds and the max value in milliseconds, the same as the other HdrHistogram tools.
+		if _, err := fmt.Fprintf(l.w, "Tag=%s,%.3f,%.3f,%.3f,%s\n",
+			op,
+			float64(start.UnixNano())/float64(time.Second),
+			end.Sub(start).Seconds(),
+			float64(h.Max())/float64(time.Millisecond),
+			payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergedHistogram is the merge of all the intervals of one operation.
+type MergedHistogram struct {
+	*hdrhistogram.Histogram
+	Start time.Time
+	End   time.Time
+}
+
+// GetInfo returns the info of the merged histogram, the throughput is computed
+// over the span from the earliest interval start to the latest interval end.
+func (m *MergedHistogram) GetInfo() HistInfo {
+	return newHistInfo(m.Histogram, int64(m.Mean()*float64(m.TotalCount())), m.End.Sub(m.Start).Seconds())
+}
+
+// MergeHistogramLogs reads interval logs written by HistogramLogWriter, possibly
+// from several clients, and merges the intervals of every operation.
+func MergeHistogramLogs(logs ...io.Reader) (map[string]*MergedHistogram, error) {
+	merged := make(map[string]*MergedHistogram)
+	for _, log := range logs {
+		reader := hdrhistogram.NewHistogramLogReader(log)
+		for {
+			h, err := reader.NextIntervalHistogram()
+			if err != nil {
+				return nil, err
+			}
+			if h == nil {
+				break
+			}
+			start := time.Unix(0, h.StartTimeMs()*int64(time.Millisecond))
+			end := time.Unix(0, h.EndTimeMs()*int64(time.Millisecond))
+			m, ok := merged[h.Tag()]
+			if !ok {
+				merged[h.Tag()] = &MergedHistogram{Histogram: h, Start: start, End: end}
+				continue
+			}
+			if dropped := m.Merge(h); dropped > 0 {
+				return nil, fmt.Errorf("%d values of %s are out of the histogram range", dropped, h.Tag())
+			}
+			if start.Before(m.Start) {
+				m.Start = start
+			}
+			if end.After(m.End) {
+				m.End = end
+			}
+		}
+	}
+	return merged, nil
+}
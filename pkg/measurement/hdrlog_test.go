@@ -0,0 +1,58 @@
+package measurement
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeHistogramLogs(t *testing.T) {
+	start := time.Now()
+	logs := make([]io.Reader, 0, 2)
+	for client := 1; client <= 2; client++ {
+		var buf bytes.Buffer
+		w, err := NewHistogramLogWriter(&buf, start)
+		assert.NoError(t, err)
+
+		m := NewMeasurement()
+		for i := 0; i < 100; i++ {
+			m.Measure("new_order", time.Duration(client*10)*time.Millisecond, nil)
+		}
+		assert.NoError(t, w.Write("tpcc", start.Add(10*time.Second), m.OpSumMeasurement))
+		logs = append(logs, &buf)
+	}
+
+	merged, err := MergeHistogramLogs(logs...)
+	assert.NoError(t, err)
+	// The paired new_order_ERR histogram is empty and not written.
+	assert.Len(t, merged, 1)
+	info := merged["new_order"].GetInfo()
+	assert.Equal(t, int64(200), info.Count)
+	assert.InDelta(t, 10, info.P50, 1)
+	assert.InDelta(t, 20, info.P99, 1)
+}
+
+func TestHistogramLogIntervals(t *testing.T) {
+	start := time.Now()
+	var buf bytes.Buffer
+	w, err := NewHistogramLogWriter(&buf, start)
+	assert.NoError(t, err)
+
+	// the first interval is idle, the histogram created in the second one still starts from the first end
+	m := NewMeasurement()
+	assert.NoError(t, w.Write("tpcc", start.Add(10*time.Second), m.OpSumMeasurement))
+	m.Measure("new_order", 10*time.Millisecond, nil)
+	assert.NoError(t, w.Write("tpcc", start.Add(20*time.Second), m.OpSumMeasurement))
+	// another source sharing the log has its own intervals
+	assert.NoError(t, w.Write("ch", start.Add(20*time.Second), map[string]*Histogram{"q1": m.OpSumMeasurement["new_order"]}))
+
+	merged, err := MergeHistogramLogs(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, merged, 2)
+	assert.InDelta(t, 10, merged["new_order"].End.Sub(merged["new_order"].Start).Seconds(), 0.01)
+	assert.InDelta(t, 10, merged["new_order"].Start.Sub(start).Seconds(), 0.01)
+	assert.InDelta(t, 20, merged["q1"].End.Sub(merged["q1"].Start).Seconds(), 0.01)
+}
@@ -14,20 +14,24 @@ type Histogram struct {
 	m         sync.RWMutex
 	sum       int64
 	startTime time.Time
+	// errCounts counts the recorded errors by class, it is only used by the histograms of failed operations.
+	errCounts map[util.ErrorClass]int64
 }
 
 type HistInfo struct {
-	Elapsed float64
-	Sum     float64
-	Count   int64
-	Ops     float64
-	Avg     float64
-	P50     float64
-	P90     float64
-	P95     float64
-	P99     float64
-	P999    float64
-	Max     float64
+	Elapsed float64 `json:"elapsed"`
+	Sum     float64 `json:"sum"`
+	Count   int64   `json:"count"`
+	Ops     float64 `json:"ops"`
+	Avg     float64 `json:"avg"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P95     float64 `json:"p95"`
+	P99     float64 `json:"p99"`
+	P999    float64 `json:"p999"`
+	Max     float64 `json:"max"`
+
+	Errors map[util.ErrorClass]int64 `json:"errors,omitempty"`
 }
 
 func NewHistogram(minLat, maxLat time.Duration, sf int) *Histogram {
@@ -50,6 +54,34 @@ func (h *Histogram) Measure(rawLatency time.Duration) {
 	}
 }
 
+// CountError counts a recorded error by its class.
+func (h *Histogram) CountError(class util.ErrorClass) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	if h.errCounts == nil {
+		h.errCounts = make(map[util.ErrorClass]int64, len(util.ErrorClasses))
+	}
+	h.errCounts[class]++
+}
+
+// ErrorCounts returns the count of errors by class.
+func (h *Histogram) ErrorCounts() map[util.ErrorClass]int64 {
+	h.m.RLock()
+	defer h.m.RUnlock()
+	counts := make(map[util.ErrorClass]int64, len(h.errCounts))
+	for class, count := range h.errCounts {
+		counts[class] = count
+	}
+	return counts
+}
+
+// ResetStartTime sets the time from which the elapsed time and throughput are computed.
+func (h *Histogram) ResetStartTime(t time.Time) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.startTime = t
+}
+
 func (h *Histogram) Empty() bool {
 	h.m.Lock()
 	defer h.m.Unlock()
@@ -57,8 +89,11 @@ func (h *Histogram) Empty() bool {
 }
 
 func (h *Histogram) Summary() []string {
-	res := h.GetInfo()
+	return h.GetInfo().Summary()
+}
 
+// Summary formats the info as Takes(s), Count, TPM, Sum(ms), Avg(ms), 50th(ms), 90th(ms), 95th(ms), 99th(ms), 99.9th(ms), Max(ms).
+func (res HistInfo) Summary() []string {
 	return []string{
 		util.FloatToOneString(res.Elapsed),
 		util.IntToString(res.Count),
@@ -77,9 +112,33 @@ func (h *Histogram) Summary() []string {
 func (h *Histogram) GetInfo() HistInfo {
 	h.m.RLock()
 	defer h.m.RUnlock()
-	sum := time.Duration(h.sum).Seconds() * 1000
+	info := newHistInfo(h.Histogram, h.sum, time.Now().Sub(h.startTime).Seconds())
+	if len(h.errCounts) > 0 {
+		info.Errors = make(map[util.ErrorClass]int64, len(h.errCounts))
+		for class, count := range h.errCounts {
+			info.Errors[class] = count
+		}
+	}
+	return info
+}
+
+// StartTime returns the time from which the elapsed time is computed.
+func (h *Histogram) StartTime() time.Time {
+	h.m.RLock()
+	defer h.m.RUnlock()
+	return h.startTime
+}
+
+// snapshot returns a copy of the underlying distribution.
+func (h *Histogram) snapshot() *hdrhistogram.Histogram {
+	h.m.RLock()
+	defer h.m.RUnlock()
+	return hdrhistogram.Import(h.Export())
+}
+
+func newHistInfo(h *hdrhistogram.Histogram, sumNanos int64, elapsed float64) HistInfo {
+	sum := time.Duration(sumNanos).Seconds() * 1000
 	avg := time.Duration(h.Mean()).Seconds() * 1000
-	elapsed := time.Now().Sub(h.startTime).Seconds()
 	count := h.TotalCount()
 	ops := float64(count) / elapsed
 	info := HistInfo{
@@ -2,8 +2,12 @@ package measurement
 
 import (
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/go-tpc/pkg/util"
 )
 
 func TestHist(t *testing.T) {
@@ -16,3 +20,38 @@ func TestHist(t *testing.T) {
 	h.Measure(time.Minute * 8)
 	t.Logf("%+v", h.Summary())
 }
+
+func TestFinishWarmUp(t *testing.T) {
+	m := NewMeasurement()
+	m.EnableWarmUp(true)
+	m.Measure("op", time.Millisecond, nil)
+	if len(m.OpSumMeasurement) != 0 {
+		t.Fatalf("expect no samples during warm-up, got %d histograms", len(m.OpSumMeasurement))
+	}
+
+	m.FinishWarmUp()
+	if !m.IsWarmUpFinished() {
+		t.Fatalf("expect warm-up finished")
+	}
+	m.Measure("op", time.Millisecond, nil)
+	if count := m.OpSumMeasurement["op"].GetInfo().Count; count != 1 {
+		t.Fatalf("expect 1 sample after warm-up, got %d", count)
+	}
+}
+
+func TestErrorClassLines(t *testing.T) {
+	m := NewMeasurement()
+	m.Measure("new_order", time.Millisecond, nil)
+	m.Measure("new_order", time.Millisecond, &mysql.MySQLError{Number: 9007})
+	m.Measure("new_order", time.Millisecond, &mysql.MySQLError{Number: 9007})
+	m.Measure("new_order", time.Millisecond, &mysql.MySQLError{Number: 1205})
+
+	lines := ErrorClassLines("[Summary] ", m.OpSumMeasurement)
+	expected := [][]string{{"[Summary] ", "NEW_ORDER_ERR", "2", "1", "0", "0"}}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("expect %v, got %v", expected, lines)
+	}
+	if errors := m.OpSumMeasurement["new_order_ERR"].GetInfo().Errors; errors[util.ErrClassConflict] != 2 {
+		t.Fatalf("expect 2 conflicts, got %v", errors)
+	}
+}
@@ -2,9 +2,13 @@ package measurement
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/pingcap/go-tpc/pkg/util"
 )
 
 const (
@@ -22,14 +26,17 @@ type Measurement struct {
 	SigFigs          int
 	OpCurMeasurement map[string]*Histogram
 	OpSumMeasurement map[string]*Histogram
-}
 
-func (m *Measurement) getHist(op string, err error, current bool) *Histogram {
-	opMeasurement := m.OpSumMeasurement
-	if current {
-		opMeasurement = m.OpCurMeasurement
-	}
+	// opStepMeasurement is only recorded after BeginStep is called.
+	opStepMeasurement map[string]*Histogram
+
+	intervalHooks []func(opMeasurement map[string]*Histogram)
+
+	// metrics is nil unless the measurement is created WithMetrics.
+	metrics *metrics
+}
 
+func (m *Measurement) getHist(opMeasurement map[string]*Histogram, op string, err error) *Histogram {
 	// Create hist of {op} and {op}_ERR at the same time, or else the TPM would be incorrect
 	opPairedKey := fmt.Sprintf("%s_ERR", op)
 	if err != nil {
@@ -51,8 +58,54 @@ func (m *Measurement) getHist(op string, err error, current bool) *Histogram {
 }
 
 func (m *Measurement) measure(op string, err error, lan time.Duration) {
-	m.getHist(op, err, true).Measure(lan)
-	m.getHist(op, err, false).Measure(lan)
+	m.RLock()
+	opCurMeasurement, opStepMeasurement := m.OpCurMeasurement, m.opStepMeasurement
+	m.RUnlock()
+	var class util.ErrorClass
+	if err != nil {
+		class = util.ClassifyError(err)
+	}
+	record := func(opMeasurement map[string]*Histogram) {
+		h := m.getHist(opMeasurement, op, err)
+		h.Measure(lan)
+		if err != nil {
+			h.CountError(class)
+		}
+	}
+	record(opCurMeasurement)
+	record(m.OpSumMeasurement)
+	if opStepMeasurement != nil {
+		record(opStepMeasurement)
+	}
+	if m.metrics != nil {
+		m.metrics.observe(op, class, lan)
+	}
+}
+
+// ErrorClassHeaders are the headers of the lines returned by ErrorClassLines, in the order of util.ErrorClasses.
+var ErrorClassHeaders = []string{"Prefix", "Operation", "Conflict", "Timeout", "Connection", "Other"}
+
+// ErrorClassLines returns the error counts by class of every failed operation in opMeasurement.
+func ErrorClassLines(prefix string, opMeasurement map[string]*Histogram) [][]string {
+	ops := make([]string, 0, len(opMeasurement))
+	for op := range opMeasurement {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	lines := [][]string{}
+	for _, op := range ops {
+		counts := opMeasurement[op].ErrorCounts()
+		if len(counts) == 0 {
+			continue
+		}
+		line := []string{prefix, strings.ToUpper(op)}
+		for _, class := range util.ErrorClasses {
+			line = append(line, util.IntToString(counts[class]))
+		}
+		lines = append(lines, line)
+	}
+	return lines
 }
 
 func (m *Measurement) takeCurMeasurement() (ret map[string]*Histogram) {
@@ -62,6 +115,21 @@ func (m *Measurement) takeCurMeasurement() (ret map[string]*Histogram) {
 	return
 }
 
+// BeginStep starts recording a separate summary for the current step of a load profile.
+func (m *Measurement) BeginStep() {
+	m.Lock()
+	defer m.Unlock()
+	m.opStepMeasurement = make(map[string]*Histogram, 16)
+}
+
+// TakeStepMeasurement returns the measurement of the current step and starts a new one.
+func (m *Measurement) TakeStepMeasurement() (ret map[string]*Histogram) {
+	m.Lock()
+	defer m.Unlock()
+	ret, m.opStepMeasurement = m.opStepMeasurement, make(map[string]*Histogram, 16)
+	return
+}
+
 func (m *Measurement) getOpName() []string {
 	m.RLock()
 	defer m.RUnlock()
@@ -86,6 +154,26 @@ func (m *Measurement) Output(ifSummaryReport bool, outputStyle string, outputFun
 	m.RLock()
 	defer m.RUnlock()
 	outputFunc(outputStyle, "[Current] ", opCurMeasurement)
+	for _, hook := range m.intervalHooks {
+		hook(opCurMeasurement)
+	}
+}
+
+// FlushInterval passes the measurement since the last output to the interval hooks without printing it.
+func (m *Measurement) FlushInterval() {
+	var opCurMeasurement = m.takeCurMeasurement()
+	m.RLock()
+	defer m.RUnlock()
+	for _, hook := range m.intervalHooks {
+		hook(opCurMeasurement)
+	}
+}
+
+// AddIntervalHook adds a function called with the interval measurement every time it is output.
+func (m *Measurement) AddIntervalHook(hook func(opMeasurement map[string]*Histogram)) {
+	m.Lock()
+	defer m.Unlock()
+	m.intervalHooks = append(m.intervalHooks, hook)
 }
 
 // EnableWarmUp sets whether to enable warm-up.
@@ -102,6 +190,20 @@ func (m *Measurement) IsWarmUpFinished() bool {
 	return atomic.LoadInt32(&m.warmUp) == 0
 }
 
+// FinishWarmUp ends the warm-up and resets the start time of every histogram,
+// so the throughput in the summary only reflects the steady state.
+func (m *Measurement) FinishWarmUp() {
+	now := time.Now()
+	m.RLock()
+	for _, hists := range []map[string]*Histogram{m.OpCurMeasurement, m.OpSumMeasurement, m.opStepMeasurement} {
+		for _, h := range hists {
+			h.ResetStartTime(now)
+		}
+	}
+	m.RUnlock()
+	m.EnableWarmUp(false)
+}
+
 // Measure measures the operation.
 func (m *Measurement) Measure(op string, lan time.Duration, err error) {
 	if !m.IsWarmUpFinished() {
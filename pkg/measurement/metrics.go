@@ -0,0 +1,73 @@
+package measurement
+
+import (
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	opDurationVec = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tpc",
+			Name:      "op_duration_seconds",
+			Help:      "The latency of successful operations",
+			// 0.5ms to about 4.4min
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 20),
+			// Also expose a native histogram, whose buckets grow by at most 10%, to the scrapers supporting it.
+			NativeHistogramBucketFactor: 1.1,
+		}, []string{"workload", "op"},
+	)
+	opTotalVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tpc",
+			Name:      "op_total",
+			Help:      "The total count of successful operations, e.g. committed transactions",
+		}, []string{"workload", "op"},
+	)
+	opErrorTotalVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tpc",
+			Name:      "op_error_total",
+			Help:      "The total count of failed operations, e.g. failed transactions, by error class",
+		}, []string{"workload", "op", "class"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(opDurationVec, opTotalVec, opErrorTotalVec)
+}
+
+// metrics exports the operations of one workload to Prometheus.
+type metrics struct {
+	duration   prometheus.ObserverVec
+	total      *prometheus.CounterVec
+	errorTotal *prometheus.CounterVec
+}
+
+func newMetrics(workload string) *metrics {
+	labels := prometheus.Labels{"workload": workload}
+	return &metrics{
+		duration:   opDurationVec.MustCurryWith(labels),
+		total:      opTotalVec.MustCurryWith(labels),
+		errorTotal: opErrorTotalVec.MustCurryWith(labels),
+	}
+}
+
+// observe records an operation, class is empty if the operation succeeded.
+func (m *metrics) observe(op string, class util.ErrorClass, lan time.Duration) {
+	if len(class) > 0 {
+		m.errorTotal.WithLabelValues(op, string(class)).Inc()
+		return
+	}
+	m.duration.WithLabelValues(op).Observe(lan.Seconds())
+	m.total.WithLabelValues(op).Inc()
+}
+
+// WithMetrics exports the measured operations to Prometheus labelled by the workload name.
+func WithMetrics(workload string) func(*Measurement) {
+	return func(m *Measurement) {
+		m.metrics = newMetrics(workload)
+	}
+}
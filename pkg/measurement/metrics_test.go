@@ -0,0 +1,26 @@
+package measurement
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasurementMetrics(t *testing.T) {
+	m := NewMeasurement(WithMetrics("metrics_test"))
+	m.Measure("q1", 10*time.Millisecond, nil)
+	m.Measure("q1", 20*time.Millisecond, nil)
+	m.Measure("q1", time.Millisecond, errors.New("mock error"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(opTotalVec.WithLabelValues("metrics_test", "q1")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(opErrorTotalVec.WithLabelValues("metrics_test", "q1", "other")))
+	assert.Equal(t, 1, testutil.CollectAndCount(opDurationVec.MustCurryWith(map[string]string{"workload": "metrics_test"})))
+
+	// operations measured during warm-up are not exported
+	m.EnableWarmUp(true)
+	m.Measure("q1", 10*time.Millisecond, nil)
+	assert.Equal(t, 2.0, testutil.ToFloat64(opTotalVec.WithLabelValues("metrics_test", "q1")))
+}
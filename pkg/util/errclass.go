@@ -0,0 +1,79 @@
+package util
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// ErrorClass is the category of a database error.
+type ErrorClass string
+
+const (
+	// ErrClassConflict is a retryable conflict, e.g. deadlock, write conflict or serialization failure.
+	ErrClassConflict ErrorClass = "conflict"
+	// ErrClassTimeout is a lock wait or statement timeout.
+	ErrClassTimeout ErrorClass = "timeout"
+	// ErrClassConnection is a broken or refused connection.
+	ErrClassConnection ErrorClass = "connection"
+	// ErrClassOther is any other error.
+	ErrClassOther ErrorClass = "other"
+)
+
+// ErrorClasses lists all the error classes in output order.
+var ErrorClasses = []ErrorClass{ErrClassConflict, ErrClassTimeout, ErrClassConnection, ErrClassOther}
+
+var mysqlErrorClasses = map[uint16]ErrorClass{
+	1205: ErrClassTimeout,    // ER_LOCK_WAIT_TIMEOUT
+	1213: ErrClassConflict,   // ER_LOCK_DEADLOCK
+	1317: ErrClassTimeout,    // ER_QUERY_INTERRUPTED
+	3024: ErrClassTimeout,    // ER_QUERY_TIMEOUT
+	1040: ErrClassConnection, // ER_CON_COUNT_ERROR
+	1053: ErrClassConnection, // ER_SERVER_SHUTDOWN
+	8002: ErrClassConflict,   // TiDB: SELECT FOR UPDATE can not be retried
+	8022: ErrClassConflict,   // TiDB: transaction retry failed
+	8028: ErrClassConflict,   // TiDB: information schema changed
+	9001: ErrClassTimeout,    // TiDB: PD server timeout
+	9002: ErrClassTimeout,    // TiDB: TiKV server timeout
+	9007: ErrClassConflict,   // TiDB: write conflict
+}
+
+// ClassifyError classifies err by the MySQL error number or the PostgreSQL SQLSTATE.
+func ClassifyError(err error) ErrorClass {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		if class, ok := mysqlErrorClasses[myErr.Number]; ok {
+			return class
+		}
+		return ErrClassOther
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch code := string(pqErr.Code); {
+		case code == "40001" || code == "40P01": // serialization_failure, deadlock_detected
+			return ErrClassConflict
+		case code == "55P03" || code == "57014": // lock_not_available, query_canceled
+			return ErrClassTimeout
+		case strings.HasPrefix(code, "08") || strings.HasPrefix(code, "57P"): // connection_exception, operator_intervention
+			return ErrClassConnection
+		}
+		return ErrClassOther
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrClassTimeout
+	}
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr) {
+		return ErrClassConnection
+	}
+	return ErrClassOther
+}
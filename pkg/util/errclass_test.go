@@ -0,0 +1,35 @@
+package util
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected ErrorClass
+	}{
+		{&mysql.MySQLError{Number: 1213}, ErrClassConflict},
+		{fmt.Errorf("exec update stock failed %w", &mysql.MySQLError{Number: 9007}), ErrClassConflict},
+		{&mysql.MySQLError{Number: 1205}, ErrClassTimeout},
+		{&mysql.MySQLError{Number: 1062}, ErrClassOther},
+		{&pq.Error{Code: "40001"}, ErrClassConflict},
+		{&pq.Error{Code: "57014"}, ErrClassTimeout},
+		{&pq.Error{Code: "08006"}, ErrClassConnection},
+		{&pq.Error{Code: "23505"}, ErrClassOther},
+		{fmt.Errorf("begin failed %w", driver.ErrBadConn), ErrClassConnection},
+		{context.DeadlineExceeded, ErrClassTimeout},
+		{errors.New("item not found"), ErrClassOther},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, ClassifyError(tc.err), tc.err.Error())
+	}
+}
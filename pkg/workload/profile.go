@@ -0,0 +1,85 @@
+package workload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadStep is one step of a load profile.
+type LoadStep struct {
+	Threads  int
+	Duration time.Duration
+}
+
+type loadStepJSON struct {
+	Threads  int    `json:"threads"`
+	Duration string `json:"duration"`
+}
+
+// ParseLoadProfile parses a load profile like "16:5m,32:5m,64:5m", each step is threads:duration.
+func ParseLoadProfile(s string) ([]LoadStep, error) {
+	var steps []LoadStep
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid load step %q, expect threads:duration", item)
+		}
+		threads, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid threads in load step %q: %v", item, err)
+		}
+		duration, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration in load step %q: %v", item, err)
+		}
+		steps = append(steps, LoadStep{Threads: threads, Duration: duration})
+	}
+	return steps, validateLoadProfile(steps)
+}
+
+// ReadLoadProfile reads a load profile from a JSON file like
+// {"steps": [{"threads": 16, "duration": "5m"}, {"threads": 32, "duration": "5m"}]}.
+func ReadLoadProfile(path string) ([]LoadStep, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var profile struct {
+		Steps []loadStepJSON `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("parse load profile %s failed: %v", path, err)
+	}
+	steps := make([]LoadStep, 0, len(profile.Steps))
+	for _, step := range profile.Steps {
+		duration, err := time.ParseDuration(step.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q in load profile %s: %v", step.Duration, path, err)
+		}
+		steps = append(steps, LoadStep{Threads: step.Threads, Duration: duration})
+	}
+	return steps, validateLoadProfile(steps)
+}
+
+func validateLoadProfile(steps []LoadStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("empty load profile")
+	}
+	for i, step := range steps {
+		if step.Threads <= 0 {
+			return fmt.Errorf("load step %d: threads must be positive, got %d", i+1, step.Threads)
+		}
+		if step.Duration <= 0 {
+			return fmt.Errorf("load step %d: duration must be positive, got %v", i+1, step.Duration)
+		}
+	}
+	return nil
+}
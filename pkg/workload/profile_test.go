@@ -0,0 +1,36 @@
+package workload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLoadProfile(t *testing.T) {
+	steps, err := ParseLoadProfile("16:5m, 32:5m,64:30s")
+	assert.NoError(t, err)
+	assert.Equal(t, []LoadStep{
+		{Threads: 16, Duration: 5 * time.Minute},
+		{Threads: 32, Duration: 5 * time.Minute},
+		{Threads: 64, Duration: 30 * time.Second},
+	}, steps)
+
+	for _, s := range []string{"", "16", "x:5m", "16:5", "0:5m"} {
+		_, err := ParseLoadProfile(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestReadLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"steps": [{"threads": 8, "duration": "1m"}, {"threads": 16, "duration": "2m"}]}`), 0644))
+	steps, err := ReadLoadProfile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []LoadStep{
+		{Threads: 8, Duration: time.Minute},
+		{Threads: 16, Duration: 2 * time.Minute},
+	}, steps)
+}
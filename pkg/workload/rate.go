@@ -0,0 +1,61 @@
+package workload
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type intendedStartKey struct{}
+
+// RateLimiter is a global token scheduler for open-loop execution. It hands
+// out evenly spaced start times shared by all workers, so the offered load
+// does not depend on how fast the database responds.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a RateLimiter issuing rate tokens per second.
+func NewRateLimiter(rate float64) *RateLimiter {
+	return &RateLimiter{
+		interval: time.Duration(float64(time.Second) / rate),
+		next:     time.Now(),
+	}
+}
+
+// Wait blocks until the next scheduled slot and returns its intended start time.
+// If the workers are behind schedule, it returns immediately with a start time
+// in the past, so the queueing delay is counted in the measured latency.
+func (r *RateLimiter) Wait(ctx context.Context) (time.Time, error) {
+	r.mu.Lock()
+	intended := r.next
+	r.next = r.next.Add(r.interval)
+	r.mu.Unlock()
+
+	if d := time.Until(intended); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return intended, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return intended, nil
+}
+
+// WithIntendedStart returns a context carrying the scheduled start time of the next operation.
+func WithIntendedStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, intendedStartKey{}, start)
+}
+
+// StartTime returns the time an operation should be measured from. It is the
+// intended start time in rate-limited mode and the current time otherwise.
+func StartTime(ctx context.Context) time.Time {
+	if start, ok := ctx.Value(intendedStartKey{}).(time.Time); ok {
+		return start
+	}
+	return time.Now()
+}
@@ -0,0 +1,44 @@
+package workload
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	r := NewRateLimiter(100)
+	first, err := r.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last time.Time
+	for i := 0; i < 10; i++ {
+		last, err = r.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := last.Sub(first); d != 100*time.Millisecond {
+		t.Fatalf("expect 100ms between intended starts, got %v", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r = NewRateLimiter(0.1)
+	r.Wait(ctx)
+	if _, err := r.Wait(ctx); err == nil {
+		t.Fatalf("expect error on cancelled context")
+	}
+}
+
+func TestStartTime(t *testing.T) {
+	start := time.Now().Add(-time.Second)
+	ctx := WithIntendedStart(context.Background(), start)
+	if got := StartTime(ctx); !got.Equal(start) {
+		t.Fatalf("expect %v, got %v", start, got)
+	}
+	if got := StartTime(context.Background()); got.Before(start) {
+		t.Fatalf("expect current time, got %v", got)
+	}
+}
@@ -2,6 +2,8 @@ package workload
 
 import (
 	"context"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
 )
 
 // Workloader is the interface for running customized workload
@@ -22,3 +24,15 @@ type Workloader interface {
 	FinishPlanReplayerDump() error
 	Exec(sql string) error
 }
+
+// MeasurementProvider is implemented by workloaders that expose their response time
+// measurement, so run-level features like load profiles can report on it.
+type MeasurementProvider interface {
+	Measurement() *measurement.Measurement
+}
+
+// ResultProvider is implemented by workloaders that compute workload specific
+// results from their measurement, like tpmC for TPC-C.
+type ResultProvider interface {
+	Results() map[string]float64
+}
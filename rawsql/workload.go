@@ -12,6 +12,7 @@ import (
 	replayer "github.com/pingcap/go-tpc/pkg/plan-replayer"
 	"github.com/pingcap/go-tpc/pkg/util"
 	"github.com/pingcap/go-tpc/pkg/workload"
+	"github.com/pingcap/go-tpc/tpch"
 )
 
 type contextKey string
@@ -43,6 +44,9 @@ type Workloader struct {
 	measurement *measurement.Measurement
 
 	PlanReplayerRunner *replayer.PlanReplayerRunner
+
+	// Comparer compares the results of the queries with another database if it's set.
+	Comparer *tpch.Comparer
 }
 
 var _ workload.Workloader = &Workloader{}
@@ -55,7 +59,7 @@ func NewWorkloader(db *sql.DB, cfg *Config) workload.Workloader {
 			m.MinLatency = 100 * time.Microsecond
 			m.MaxLatency = 20 * time.Minute
 			m.SigFigs = 3
-		}),
+		}, measurement.WithMetrics("rawsql")),
 	}
 }
 
@@ -110,7 +114,7 @@ func (w *Workloader) Run(ctx context.Context, threadID int) error {
 		query = "explain analyze\n" + query
 	}
 
-	start := time.Now()
+	start := workload.StartTime(ctx)
 	rows, err := s.Conn.QueryContext(ctx, query)
 	w.measurement.Measure(queryName, time.Since(start), err)
 	if err != nil {
@@ -126,6 +130,13 @@ func (w *Workloader) Run(ctx context.Context, threadID int) error {
 	}
 
 	defer rows.Close()
+	if w.Comparer != nil {
+		cols, got, err := tpch.ScanRows(rows)
+		if err != nil {
+			return fmt.Errorf("scan query %s failed %v", queryName, err)
+		}
+		w.Comparer.Compare(ctx, queryName, query, cols, got)
+	}
 	return nil
 }
 
@@ -158,6 +169,14 @@ func outputMeasurement(outputStyle string, prefix string, opMeasurement map[stri
 
 func (w *Workloader) OutputStats(ifSummaryReport bool) {
 	w.measurement.Output(ifSummaryReport, w.cfg.OutputStyle, outputMeasurement)
+	if ifSummaryReport && w.Comparer != nil {
+		w.Comparer.OutputStats(w.cfg.OutputStyle)
+	}
+}
+
+// Measurement returns the response time measurement of the workload.
+func (w *Workloader) Measurement() *measurement.Measurement {
+	return w.measurement
 }
 
 func (w *Workloader) DBName() string {
@@ -0,0 +1,134 @@
+package ssb
+
+import (
+	"context"
+	"fmt"
+)
+
+var allTables = []string{"lineorder", "customer", "supplier", "part", "dates"}
+
+func (w *Workloader) createTableDDL(ctx context.Context, query string, tableName string) error {
+	s := w.getState(ctx)
+	fmt.Printf("creating %s\n", tableName)
+	if _, err := s.Conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	if w.cfg.TiFlashReplica != 0 {
+		fmt.Printf("creating tiflash replica for %s\n", tableName)
+		replicaSQL := fmt.Sprintf("ALTER TABLE %s SET TIFLASH REPLICA %d", tableName, w.cfg.TiFlashReplica)
+		if _, err := s.Conn.ExecContext(ctx, replicaSQL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTables creates the star schema, the date table is named dates since date is a keyword.
+func (w *Workloader) createTables(ctx context.Context) error {
+	query := `
+CREATE TABLE IF NOT EXISTS lineorder (
+    LO_ORDERKEY BIGINT NOT NULL,
+    LO_LINENUMBER INTEGER NOT NULL,
+    LO_CUSTKEY BIGINT NOT NULL,
+    LO_PARTKEY BIGINT NOT NULL,
+    LO_SUPPKEY BIGINT NOT NULL,
+    LO_ORDERDATE INTEGER NOT NULL,
+    LO_ORDERPRIORITY CHAR(15) NOT NULL,
+    LO_SHIPPRIORITY INTEGER NOT NULL,
+    LO_QUANTITY INTEGER NOT NULL,
+    LO_EXTENDEDPRICE DECIMAL(15, 2) NOT NULL,
+    LO_ORDTOTALPRICE DECIMAL(15, 2) NOT NULL,
+    LO_DISCOUNT INTEGER NOT NULL,
+    LO_REVENUE DECIMAL(15, 2) NOT NULL,
+    LO_SUPPLYCOST DECIMAL(15, 2) NOT NULL,
+    LO_TAX INTEGER NOT NULL,
+    LO_COMMITDATE INTEGER NOT NULL,
+    LO_SHIPMODE CHAR(10) NOT NULL,
+    PRIMARY KEY (LO_ORDERKEY, LO_LINENUMBER)
+)`
+	if err := w.createTableDDL(ctx, query, "lineorder"); err != nil {
+		return err
+	}
+
+	query = `
+CREATE TABLE IF NOT EXISTS customer (
+    C_CUSTKEY BIGINT NOT NULL,
+    C_NAME VARCHAR(25) NOT NULL,
+    C_ADDRESS VARCHAR(40) NOT NULL,
+    C_CITY CHAR(10) NOT NULL,
+    C_NATION CHAR(15) NOT NULL,
+    C_REGION CHAR(12) NOT NULL,
+    C_PHONE CHAR(15) NOT NULL,
+    C_MKTSEGMENT CHAR(10) NOT NULL,
+    PRIMARY KEY (C_CUSTKEY)
+)`
+	if err := w.createTableDDL(ctx, query, "customer"); err != nil {
+		return err
+	}
+
+	query = `
+CREATE TABLE IF NOT EXISTS supplier (
+    S_SUPPKEY BIGINT NOT NULL,
+    S_NAME CHAR(25) NOT NULL,
+    S_ADDRESS VARCHAR(40) NOT NULL,
+    S_CITY CHAR(10) NOT NULL,
+    S_NATION CHAR(15) NOT NULL,
+    S_REGION CHAR(12) NOT NULL,
+    S_PHONE CHAR(15) NOT NULL,
+    PRIMARY KEY (S_SUPPKEY)
+)`
+	if err := w.createTableDDL(ctx, query, "supplier"); err != nil {
+		return err
+	}
+
+	query = `
+CREATE TABLE IF NOT EXISTS part (
+    P_PARTKEY BIGINT NOT NULL,
+    P_NAME VARCHAR(22) NOT NULL,
+    P_MFGR CHAR(6) NOT NULL,
+    P_CATEGORY CHAR(7) NOT NULL,
+    P_BRAND1 CHAR(9) NOT NULL,
+    P_COLOR VARCHAR(11) NOT NULL,
+    P_TYPE VARCHAR(25) NOT NULL,
+    P_SIZE INTEGER NOT NULL,
+    P_CONTAINER CHAR(10) NOT NULL,
+    PRIMARY KEY (P_PARTKEY)
+)`
+	if err := w.createTableDDL(ctx, query, "part"); err != nil {
+		return err
+	}
+
+	query = `
+CREATE TABLE IF NOT EXISTS dates (
+    D_DATEKEY INTEGER NOT NULL,
+    D_DATE CHAR(18) NOT NULL,
+    D_DAYOFWEEK CHAR(9) NOT NULL,
+    D_MONTH CHAR(9) NOT NULL,
+    D_YEAR INTEGER NOT NULL,
+    D_YEARMONTHNUM INTEGER NOT NULL,
+    D_YEARMONTH CHAR(7) NOT NULL,
+    D_DAYNUMINWEEK INTEGER NOT NULL,
+    D_DAYNUMINMONTH INTEGER NOT NULL,
+    D_DAYNUMINYEAR INTEGER NOT NULL,
+    D_MONTHNUMINYEAR INTEGER NOT NULL,
+    D_WEEKNUMINYEAR INTEGER NOT NULL,
+    D_SELLINGSEASON VARCHAR(12) NOT NULL,
+    D_LASTDAYINWEEKFL INTEGER NOT NULL,
+    D_LASTDAYINMONTHFL INTEGER NOT NULL,
+    D_HOLIDAYFL INTEGER NOT NULL,
+    D_WEEKDAYFL INTEGER NOT NULL,
+    PRIMARY KEY (D_DATEKEY)
+)`
+	return w.createTableDDL(ctx, query, "dates")
+}
+
+func (w *Workloader) dropTable(ctx context.Context) error {
+	s := w.getState(ctx)
+	for _, tbl := range allTables {
+		fmt.Printf("DROP TABLE IF EXISTS %s\n", tbl)
+		if _, err := s.Conn.ExecContext(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", tbl)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
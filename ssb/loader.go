@@ -0,0 +1,210 @@
+package ssb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/sink"
+	"github.com/pingcap/go-tpc/tpch/dbgen"
+	"github.com/pingcap/go-tpc/tpch/dbgen/dist"
+)
+
+var (
+	// nations and regions are indexed by the nation keys of dbgen.
+	nations []string
+	regions []string
+
+	// the dates table covers the order and commit dates of dbgen.
+	startDate = time.Date(1992, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate   = time.Date(1998, 12, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func init() {
+	// the weights of the nations accumulate to their region keys
+	var region int32
+	for _, nation := range dist.Maps["nations"] {
+		region += nation.Weight
+		nations = append(nations, nation.Text)
+		regions = append(regions, dist.Maps["regions"][region].Text)
+	}
+}
+
+// city returns one of the 10 cities of the nation like SSB, the first 9 characters of the
+// nation padded with spaces and a digit.
+func city(nation string, key int64) string {
+	return fmt.Sprintf("%-9.9s%d", nation, key%10)
+}
+
+// dateKey converts the date like 1992-01-02 to 19920102.
+func dateKey(date string) int64 {
+	key, _ := strconv.ParseInt(strings.ReplaceAll(date, "-", ""), 10, 64)
+	return key
+}
+
+type sinkLoader struct {
+	sink.Sink
+	ctx context.Context
+}
+
+func (l *sinkLoader) Flush() error {
+	return l.Sink.Flush(l.ctx)
+}
+
+type lineOrderLoader struct {
+	sinkLoader
+}
+
+// Load writes the lineitems of the order, the discount and the tax are in percent, and the supply
+// cost is 60% of the retail price of the part like SSB.
+func (l *lineOrderLoader) Load(item interface{}) error {
+	order := item.(*dbgen.Order)
+	for _, line := range order.Lines {
+		ePrice := int64(line.EPrice)
+		if err := l.WriteRow(l.ctx,
+			int64(line.OKey),
+			int64(line.LCnt),
+			int64(order.CustKey),
+			int64(line.PartKey),
+			int64(line.SuppKey),
+			dateKey(order.Date),
+			order.OrderPriority,
+			order.ShipPriority,
+			int64(line.Quantity),
+			dbgen.FmtMoney(line.EPrice),
+			dbgen.FmtMoney(order.TotalPrice),
+			int64(line.Discount),
+			fmtMoney(ePrice*(100-int64(line.Discount))/100),
+			fmtMoney(dbgen.RetailPrice(int64(line.PartKey))*6/10),
+			int64(line.Tax),
+			dateKey(line.CDate),
+			line.ShipMode,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fmtMoney(cents int64) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+type custLoader struct {
+	sinkLoader
+}
+
+func (c *custLoader) Load(item interface{}) error {
+	cust := item.(*dbgen.Cust)
+	nation := nations[cust.NationCode]
+	return c.WriteRow(c.ctx,
+		int64(cust.CustKey),
+		cust.Name,
+		cust.Address,
+		city(nation, int64(cust.CustKey)),
+		nation,
+		regions[cust.NationCode],
+		cust.Phone,
+		cust.MktSegment,
+	)
+}
+
+type suppLoader struct {
+	sinkLoader
+}
+
+func (s *suppLoader) Load(item interface{}) error {
+	supp := item.(*dbgen.Supp)
+	nation := nations[supp.NationCode]
+	return s.WriteRow(s.ctx,
+		int64(supp.SuppKey),
+		supp.Name,
+		supp.Address,
+		city(nation, int64(supp.SuppKey)),
+		nation,
+		regions[supp.NationCode],
+		supp.Phone,
+	)
+}
+
+type partLoader struct {
+	sinkLoader
+}
+
+// Load writes the part with the SSB hierarchy MFGR#m > MFGR#mn > MFGR#mnk derived from the
+// manufacturer m and the brand mn of dbgen, the brands are split into 40 ones by the part key.
+func (p *partLoader) Load(item interface{}) error {
+	part := item.(*dbgen.Part)
+	words := strings.Fields(part.Name)
+	category := "MFGR#" + strings.TrimPrefix(part.Brand, "Brand#")
+	return p.WriteRow(p.ctx,
+		int64(part.PartKey),
+		strings.Join(words[:2], " "),
+		"MFGR#"+strings.TrimPrefix(part.Mfgr, "Manufacturer#"),
+		category,
+		category+strconv.FormatInt(int64(part.PartKey)%40+1, 10),
+		words[0],
+		part.Type,
+		int64(part.Size),
+		part.Container,
+	)
+}
+
+// loadDates writes the days from 1992-01-01 to 1998-12-31.
+func loadDates(ctx context.Context, s sink.Sink) error {
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		if err := s.WriteRow(ctx,
+			int64(d.Year()*10000+int(d.Month())*100+d.Day()),
+			d.Format("January 2, 2006"),
+			d.Weekday().String(),
+			d.Month().String(),
+			int64(d.Year()),
+			int64(d.Year()*100+int(d.Month())),
+			d.Format("Jan2006"),
+			int64(d.Weekday())+1,
+			int64(d.Day()),
+			int64(d.YearDay()),
+			int64(d.Month()),
+			int64((d.YearDay()-1)/7+1),
+			sellingSeason(d),
+			flag(d.Weekday() == time.Saturday),
+			flag(d.AddDate(0, 0, 1).Day() == 1),
+			flag(isHoliday(d)),
+			flag(d.Weekday() != time.Saturday && d.Weekday() != time.Sunday),
+		); err != nil {
+			return err
+		}
+	}
+	return s.Flush(ctx)
+}
+
+func sellingSeason(d time.Time) string {
+	switch d.Month() {
+	case time.December:
+		return "Christmas"
+	case time.January, time.February:
+		return "Winter"
+	case time.March, time.April, time.May:
+		return "Spring"
+	case time.June, time.July, time.August:
+		return "Summer"
+	default:
+		return "Fall"
+	}
+}
+
+// isHoliday returns whether the day is New Year's Day, Independence Day or Christmas.
+func isHoliday(d time.Time) bool {
+	return (d.Month() == time.January && d.Day() == 1) ||
+		(d.Month() == time.July && d.Day() == 4) ||
+		(d.Month() == time.December && d.Day() == 25)
+}
+
+func flag(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package ssb
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/pingcap/go-tpc/tpch/dbgen"
+	"github.com/stretchr/testify/assert"
+)
+
+type rowCollector struct {
+	rows [][]interface{}
+}
+
+func (c *rowCollector) WriteRow(_ context.Context, values ...interface{}) error {
+	c.rows = append(c.rows, values)
+	return nil
+}
+
+func (c *rowCollector) Flush(context.Context) error {
+	return nil
+}
+
+func (c *rowCollector) Close(context.Context) error {
+	return nil
+}
+
+func TestNations(t *testing.T) {
+	assert.Len(t, nations, 25)
+	assert.Equal(t, "ALGERIA", nations[0])
+	assert.Equal(t, "AFRICA", regions[0])
+	assert.Equal(t, "UNITED STATES", nations[24])
+	assert.Equal(t, "AMERICA", regions[24])
+	assert.Equal(t, "UNITED KI1", city("UNITED KINGDOM", 11))
+	assert.Equal(t, "CHINA    3", city("CHINA", 3))
+}
+
+func TestLoadDates(t *testing.T) {
+	c := &rowCollector{}
+	assert.NoError(t, loadDates(context.Background(), c))
+	assert.Len(t, c.rows, 7*365+2)
+	assert.Equal(t, []interface{}{int64(19971225), "December 25, 1997", "Thursday", "December", int64(1997), int64(199712),
+		"Dec1997", int64(5), int64(25), int64(359), int64(12), int64(52), "Christmas", int64(0), int64(0), int64(1), int64(1)},
+		c.rows[5*365+2+358])
+}
+
+func TestLoaders(t *testing.T) {
+	dbgen.InitDbGen(0.01)
+	var (
+		ctx                           = context.Background()
+		lineOrders, custs, supps, pts = &rowCollector{}, &rowCollector{}, &rowCollector{}, &rowCollector{}
+	)
+	loaders := map[dbgen.Table]dbgen.Loader{
+		dbgen.TOrder: &lineOrderLoader{sinkLoader{lineOrders, ctx}},
+		dbgen.TCust:  &custLoader{sinkLoader{custs, ctx}},
+		dbgen.TSupp:  &suppLoader{sinkLoader{supps, ctx}},
+		dbgen.TPart:  &partLoader{sinkLoader{pts, ctx}},
+	}
+	assert.NoError(t, dbgen.DbGen(loaders, []dbgen.Table{dbgen.TCust, dbgen.TSupp, dbgen.TPart, dbgen.TOrder}))
+	assert.Len(t, custs.rows, 1500)
+	assert.Len(t, supps.rows, 100)
+	assert.Len(t, pts.rows, 2000)
+	assert.Len(t, lineOrders.rows, 60175)
+
+	brand := regexp.MustCompile(`^MFGR#([1-5])([1-5])([1-9]|[1-3][0-9]|40)$`)
+	for _, row := range pts.rows {
+		m := brand.FindStringSubmatch(row[4].(string))
+		if !assert.NotNil(t, m, "brand %s", row[4]) ||
+			!assert.Equal(t, []interface{}{"MFGR#" + m[1], "MFGR#" + m[1] + m[2]}, row[2:4]) {
+			break
+		}
+	}
+	for _, row := range lineOrders.rows {
+		if orderDate := row[5].(int64); orderDate < 19920101 || orderDate > 19981231 || row[11].(int64) > 10 {
+			assert.Fail(t, "invalid lineorder", "%v", row)
+			break
+		}
+	}
+}
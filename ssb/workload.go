@@ -0,0 +1,263 @@
+package ssb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/sink"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+	"github.com/pingcap/go-tpc/tpch/dbgen"
+)
+
+type contextKey string
+
+const stateKey = contextKey("ssb")
+
+// Config is the configuration for ssb workload
+type Config struct {
+	Driver         string
+	DBName         string
+	RawQueries     string
+	QueryNames     []string
+	ScaleFactor    float64
+	TiFlashReplica int
+	PrepareThreads int
+
+	// for prepare command only
+	OutputType string
+	OutputDir  string
+
+	// output style
+	OutputStyle string
+}
+
+type ssbState struct {
+	*workload.TpcState
+	queryIdx int
+}
+
+// Workloader is the Star Schema Benchmark workload, the star schema is derived from the
+// tables generated by tpch/dbgen, so the row counts of the dimension tables follow TPC-H.
+type Workloader struct {
+	db  *sql.DB
+	cfg *Config
+
+	// stats
+	measurement *measurement.Measurement
+
+	// the threads generate their chunks after thread 0 created the tables
+	createTableWg sync.WaitGroup
+	prepareErr    error
+}
+
+// NewWorkloader new work loader
+func NewWorkloader(db *sql.DB, cfg *Config) workload.Workloader {
+	w := &Workloader{
+		db:  db,
+		cfg: cfg,
+		measurement: measurement.NewMeasurement(func(m *measurement.Measurement) {
+			m.MinLatency = 100 * time.Millisecond
+			m.MaxLatency = 20 * time.Minute
+			m.SigFigs = 3
+		}, measurement.WithMetrics("ssb")),
+	}
+	w.createTableWg.Add(cfg.PrepareThreads)
+	return w
+}
+
+func (w *Workloader) getState(ctx context.Context) *ssbState {
+	s := ctx.Value(stateKey).(*ssbState)
+	return s
+}
+
+// Name return workloader name
+func (w *Workloader) Name() string {
+	return "ssb"
+}
+
+// InitThread inits thread
+func (w *Workloader) InitThread(ctx context.Context, threadID int) context.Context {
+	s := &ssbState{
+		queryIdx: threadID % len(w.cfg.QueryNames),
+		TpcState: workload.NewTpcState(ctx, w.db),
+	}
+	ctx = context.WithValue(ctx, stateKey, s)
+	return ctx
+}
+
+// CleanupThread cleans up thread
+func (w *Workloader) CleanupThread(ctx context.Context, threadID int) {
+	s := w.getState(ctx)
+	s.Conn.Close()
+}
+
+// Prepare creates the tables, then every thread generates its chunk of the tables.
+func (w *Workloader) Prepare(ctx context.Context, threadID int) error {
+	if threadID == 0 {
+		w.prepareErr = w.prepareTables(ctx)
+	}
+	w.createTableWg.Done()
+	w.createTableWg.Wait()
+	if w.prepareErr != nil {
+		return w.prepareErr
+	}
+	return w.prepareChunk(ctx, threadID)
+}
+
+func (w *Workloader) prepareTables(ctx context.Context) error {
+	if err := w.createTables(ctx); err != nil {
+		return err
+	}
+	if w.cfg.OutputType == "csv" {
+		if err := os.MkdirAll(w.cfg.OutputDir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	dbgen.InitDbGen(w.cfg.ScaleFactor)
+	return nil
+}
+
+func (w *Workloader) newSink(table string, chunk, chunks int) sink.Sink {
+	if w.cfg.OutputType == "csv" {
+		fileName := fmt.Sprintf("%s.%s.csv", w.DBName(), table)
+		if chunks > 1 {
+			fileName = fmt.Sprintf("%s.%s.%d.csv", w.DBName(), table, chunk)
+		}
+		return sink.NewCSVSink(util.CreateFile(path.Join(w.cfg.OutputDir, fileName)))
+	}
+	return sink.NewSQLSink(w.db, fmt.Sprintf("INSERT INTO %s VALUES ", table), 0, 0)
+}
+
+// prepareChunk generates the chunk threadID+1 of the tables, and the dates table in the first chunk.
+func (w *Workloader) prepareChunk(ctx context.Context, threadID int) error {
+	chunk, chunks := threadID+1, w.cfg.PrepareThreads
+	if chunk == 1 {
+		fmt.Println("generating dates")
+		if err := loadDates(ctx, w.newSink("dates", chunk, 1)); err != nil {
+			return fmt.Errorf("fail to generate dates, err: %v", err)
+		}
+	}
+	loaders := map[dbgen.Table]dbgen.Loader{
+		dbgen.TOrder: &lineOrderLoader{sinkLoader{w.newSink("lineorder", chunk, chunks), ctx}},
+		dbgen.TCust:  &custLoader{sinkLoader{w.newSink("customer", chunk, chunks), ctx}},
+		dbgen.TSupp:  &suppLoader{sinkLoader{w.newSink("supplier", chunk, chunks), ctx}},
+		dbgen.TPart:  &partLoader{sinkLoader{w.newSink("part", chunk, chunks), ctx}},
+	}
+	return dbgen.DbGenChunk(loaders, []dbgen.Table{dbgen.TCust, dbgen.TSupp, dbgen.TPart, dbgen.TOrder}, chunk, chunks)
+}
+
+// CheckPrepare checks prepare
+func (w *Workloader) CheckPrepare(ctx context.Context, threadID int) error {
+	return nil
+}
+
+// Run runs workload
+func (w *Workloader) Run(ctx context.Context, threadID int) error {
+	s := w.getState(ctx)
+	defer func() {
+		s.queryIdx++
+	}()
+	if err := s.Conn.PingContext(ctx); err != nil {
+		if err := s.RefreshConn(ctx); err != nil {
+			return err
+		}
+	}
+
+	queryName := w.cfg.QueryNames[s.queryIdx%len(w.cfg.QueryNames)]
+	query, ok := queries[queryName]
+	if !ok {
+		return fmt.Errorf("unknown query %s", queryName)
+	}
+	start := workload.StartTime(ctx)
+	rows, err := s.Conn.QueryContext(ctx, query)
+	if err == nil {
+		for rows.Next() {
+		}
+		err = rows.Err()
+		rows.Close()
+	}
+	w.measurement.Measure(queryName, time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("execute %s failed %v", queryName, err)
+	}
+	return nil
+}
+
+// Cleanup cleans up workloader
+func (w *Workloader) Cleanup(ctx context.Context, threadID int) error {
+	if threadID != 0 {
+		return nil
+	}
+	return w.dropTable(ctx)
+}
+
+// Check checks data
+func (w *Workloader) Check(ctx context.Context, threadID int) error {
+	return nil
+}
+
+func outputRtMeasurement(outputStyle string, prefix string, opMeasurement map[string]*measurement.Histogram) {
+	keys := make([]string, 0, len(opMeasurement))
+	for k := range opMeasurement {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := [][]string{}
+	for _, op := range keys {
+		hist := opMeasurement[op]
+		if !hist.Empty() {
+			lines = append(lines, []string{prefix, strings.ToUpper(op), util.FloatToTwoString(float64(hist.GetInfo().Avg)/1000) + "s"})
+		}
+	}
+
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%s: %s\n", nil, lines)
+	case util.OutputStyleTable:
+		util.RenderTable([]string{"Prefix", "Operation", "Avg(s)"}, lines)
+	case util.OutputStyleJson:
+		util.RenderJson([]string{"Prefix", "Operation", "Avg(s)"}, lines)
+	}
+}
+
+// OutputStats outputs the average response time of every query
+func (w *Workloader) OutputStats(ifSummaryReport bool) {
+	w.measurement.Output(ifSummaryReport, w.cfg.OutputStyle, outputRtMeasurement)
+}
+
+// Measurement returns the response time measurement of the workload.
+func (w *Workloader) Measurement() *measurement.Measurement {
+	return w.measurement
+}
+
+// DBName returns the name of test db.
+func (w *Workloader) DBName() string {
+	return w.cfg.DBName
+}
+
+func (w *Workloader) IsPlanReplayerDumpEnabled() bool {
+	return false
+}
+
+func (w *Workloader) PreparePlanReplayerDump() error {
+	return nil
+}
+
+func (w *Workloader) FinishPlanReplayerDump() error {
+	return nil
+}
+
+func (w *Workloader) Exec(sql string) error {
+	_, err := w.db.Exec(sql)
+	return err
+}
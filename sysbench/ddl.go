@@ -0,0 +1,81 @@
+package sysbench
+
+import (
+	"context"
+	"fmt"
+)
+
+func tableName(table int) string {
+	return fmt.Sprintf("sbtest%d", table)
+}
+
+// createTableSQL returns the DDL of the table like oltp_common.lua, the secondary index on k is
+// created after the rows are loaded.
+func (w *Workloader) createTableSQL(table int) string {
+	idDef := "INTEGER NOT NULL"
+	if w.cfg.AutoInc {
+		idDef = "INTEGER NOT NULL AUTO_INCREMENT"
+		if w.cfg.Driver == "postgres" {
+			idDef = "SERIAL"
+		}
+	}
+	// the id is in a secondary index in place of the primary key with --secondary, mysql requires
+	// the auto increment column to be indexed in the table definition.
+	idIndexDef := "PRIMARY KEY (id)"
+	if w.cfg.Secondary {
+		idIndexDef = "KEY xid (id)"
+		if w.cfg.Driver == "postgres" {
+			idIndexDef = ""
+		}
+	}
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    id %s,
+    k INTEGER DEFAULT '0' NOT NULL,
+    c CHAR(120) DEFAULT '' NOT NULL,
+    pad CHAR(60) DEFAULT '' NOT NULL`, tableName(table), idDef)
+	if len(idIndexDef) > 0 {
+		query += ",\n    " + idIndexDef
+	}
+	return query + "\n)"
+}
+
+func (w *Workloader) createTable(ctx context.Context, table int) error {
+	s := getState(ctx)
+	fmt.Printf("creating table %s\n", tableName(table))
+	_, err := s.Conn.ExecContext(ctx, w.createTableSQL(table))
+	return err
+}
+
+// createIndexes creates the secondary indexes of the loaded table, and advances the sequence of
+// the serial id of postgres past the loaded ids.
+func (w *Workloader) createIndexes(ctx context.Context, table int) error {
+	s := getState(ctx)
+	var queries []string
+	if w.cfg.Secondary && w.cfg.Driver == "postgres" {
+		queries = append(queries, fmt.Sprintf("CREATE INDEX xid_%d ON %s (id)", table, tableName(table)))
+	}
+	if w.cfg.CreateSecondary {
+		queries = append(queries, fmt.Sprintf("CREATE INDEX k_%d ON %s (k)", table, tableName(table)))
+	}
+	if w.cfg.AutoInc && w.cfg.Driver == "postgres" {
+		queries = append(queries, fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), %d)", tableName(table), w.cfg.TableSize))
+	}
+	for _, query := range queries {
+		fmt.Println(query)
+		if _, err := s.Conn.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Workloader) dropTables(ctx context.Context) error {
+	s := getState(ctx)
+	for table := 1; table <= w.cfg.Tables; table++ {
+		fmt.Printf("DROP TABLE IF EXISTS %s\n", tableName(table))
+		if _, err := s.Conn.ExecContext(ctx, "DROP TABLE IF EXISTS "+tableName(table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package sysbench
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTableSQL(t *testing.T) {
+	w := &Workloader{cfg: &Config{Driver: "mysql", AutoInc: true}}
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS sbtest1 (
+    id INTEGER NOT NULL AUTO_INCREMENT,
+    k INTEGER DEFAULT '0' NOT NULL,
+    c CHAR(120) DEFAULT '' NOT NULL,
+    pad CHAR(60) DEFAULT '' NOT NULL,
+    PRIMARY KEY (id)
+)`, w.createTableSQL(1))
+
+	w.cfg.Secondary = true
+	assert.Contains(t, w.createTableSQL(2), "id INTEGER NOT NULL AUTO_INCREMENT,")
+	assert.Contains(t, w.createTableSQL(2), "KEY xid (id)\n)")
+
+	w.cfg = &Config{Driver: "postgres", AutoInc: true, Secondary: true}
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS sbtest3 (
+    id SERIAL,
+    k INTEGER DEFAULT '0' NOT NULL,
+    c CHAR(120) DEFAULT '' NOT NULL,
+    pad CHAR(60) DEFAULT '' NOT NULL
+)`, w.createTableSQL(3))
+
+	w.cfg = &Config{Driver: "postgres"}
+	assert.Contains(t, w.createTableSQL(1), "id INTEGER NOT NULL,")
+	assert.Contains(t, w.createTableSQL(1), "PRIMARY KEY (id)")
+}
+
+func TestRandValues(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	c, pad := randC(r), randPad(r)
+	assert.Len(t, c, 119)
+	assert.Len(t, pad, 59)
+	assert.Regexp(t, regexp.MustCompile(`^(\d{11}-){9}\d{11}$`), c)
+	assert.Regexp(t, regexp.MustCompile(`^(\d{11}-){4}\d{11}$`), pad)
+}
+
+func TestNewWorkloader(t *testing.T) {
+	cfg := &Config{Workload: "oltp_read_only", Tables: 1, TableSize: 100, RangeSize: 10, Threads: 1}
+	w, err := NewWorkloader(nil, cfg)
+	assert.NoError(t, err)
+	assert.True(t, w.(*Workloader).trx)
+
+	cfg.SkipTrx = true
+	w, err = NewWorkloader(nil, cfg)
+	assert.NoError(t, err)
+	assert.False(t, w.(*Workloader).trx)
+
+	cfg.Workload = "oltp_point_select"
+	cfg.SkipTrx = false
+	w, err = NewWorkloader(nil, cfg)
+	assert.NoError(t, err)
+	assert.False(t, w.(*Workloader).trx)
+
+	cfg.Workload = "oltp_delete"
+	_, err = NewWorkloader(nil, cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,616 @@
+package sysbench
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/sink"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+)
+
+type contextKey string
+
+const stateKey = contextKey("sysbench")
+
+// Config is the configuration for sysbench workload
+type Config struct {
+	Driver  string
+	DBName  string
+	Threads int
+
+	// Workload is one of oltp_read_only, oltp_read_write, oltp_write_only, oltp_point_select,
+	// oltp_update_index and oltp_insert.
+	Workload  string
+	Tables    int
+	TableSize int
+	RangeSize int
+
+	// the number of the queries of every kind in an event like oltp_common.lua
+	PointSelects    int
+	SimpleRanges    int
+	SumRanges       int
+	OrderRanges     int
+	DistinctRanges  int
+	IndexUpdates    int
+	NonIndexUpdates int
+	DeleteInserts   int
+	RangeSelects    bool
+	SkipTrx         bool
+
+	// AutoInc makes the id an auto increment column, which is also assigned by oltp_insert
+	AutoInc bool
+
+	// for prepare command only
+	Secondary       bool
+	CreateSecondary bool
+
+	MaxMeasureLatency time.Duration
+
+	// output style
+	OutputStyle string
+}
+
+type tableStmts struct {
+	pointSelect    *sql.Stmt
+	simpleRange    *sql.Stmt
+	sumRange       *sql.Stmt
+	orderRange     *sql.Stmt
+	distinctRange  *sql.Stmt
+	indexUpdate    *sql.Stmt
+	nonIndexUpdate *sql.Stmt
+	delete         *sql.Stmt
+	insert         *sql.Stmt
+	insertAutoInc  *sql.Stmt
+}
+
+func (t *tableStmts) close() {
+	for _, stmt := range []*sql.Stmt{t.pointSelect, t.simpleRange, t.sumRange, t.orderRange, t.distinctRange,
+		t.indexUpdate, t.nonIndexUpdate, t.delete, t.insert, t.insertAutoInc} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+}
+
+type sysbenchState struct {
+	*workload.TpcState
+	stmts map[int]*tableStmts
+
+	// the transaction of the event, nil with --skip-trx
+	tx *sql.Tx
+	// the number of queries executed in the event
+	queries int64
+}
+
+func (s *sysbenchState) closeStmts() {
+	for _, stmts := range s.stmts {
+		stmts.close()
+	}
+	s.stmts = make(map[int]*tableStmts)
+}
+
+// stmt binds the statement to the transaction of the event if any.
+func (s *sysbenchState) stmt(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	if s.tx != nil {
+		return s.tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
+
+func (s *sysbenchState) exec(ctx context.Context, stmt *sql.Stmt, args ...interface{}) error {
+	if _, err := s.stmt(ctx, stmt).ExecContext(ctx, args...); err != nil {
+		return err
+	}
+	s.queries++
+	return nil
+}
+
+func (s *sysbenchState) query(ctx context.Context, stmt *sql.Stmt, args ...interface{}) error {
+	rows, err := s.stmt(ctx, stmt).QueryContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	s.queries++
+	return nil
+}
+
+// Workloader is the sysbench OLTP workload on the sbtest tables
+type Workloader struct {
+	db  *sql.DB
+	cfg *Config
+
+	event func(ctx context.Context, s *sysbenchState, table int) error
+	trx   bool
+
+	// insertSeq is the last id inserted by oltp_insert without auto increment
+	insertSeq int64
+	// queries and events count the queries of the successful events to compute the qps
+	queries int64
+	events  int64
+
+	createTableWg sync.WaitGroup
+	loadWg        sync.WaitGroup
+
+	// stats
+	measurement *measurement.Measurement
+}
+
+// NewWorkloader creates the sysbench workloader
+func NewWorkloader(db *sql.DB, cfg *Config) (workload.Workloader, error) {
+	if cfg.Tables <= 0 || cfg.TableSize <= 0 {
+		return nil, fmt.Errorf("tables and table size must be positive")
+	}
+	if cfg.RangeSize <= 0 {
+		return nil, fmt.Errorf("range size must be positive")
+	}
+
+	w := &Workloader{
+		db:        db,
+		cfg:       cfg,
+		insertSeq: int64(cfg.TableSize),
+		measurement: measurement.NewMeasurement(func(m *measurement.Measurement) {
+			m.MaxLatency = cfg.MaxMeasureLatency
+		}, measurement.WithMetrics("sysbench")),
+	}
+	switch cfg.Workload {
+	case "oltp_read_only":
+		w.event, w.trx = w.readOnly, !cfg.SkipTrx
+	case "oltp_read_write":
+		w.event, w.trx = w.readWrite, !cfg.SkipTrx
+	case "oltp_write_only":
+		w.event, w.trx = w.writeOnly, !cfg.SkipTrx
+	case "oltp_point_select":
+		w.event = w.pointSelects
+	case "oltp_update_index":
+		w.event = w.indexUpdates
+	case "oltp_insert":
+		w.event = w.insert
+	default:
+		return nil, fmt.Errorf("unknown workload %s, valid values are oltp_read_only, oltp_read_write, oltp_write_only, "+
+			"oltp_point_select, oltp_update_index and oltp_insert", cfg.Workload)
+	}
+
+	w.createTableWg.Add(cfg.Threads)
+	w.loadWg.Add(cfg.Threads)
+	return w, nil
+}
+
+func getState(ctx context.Context) *sysbenchState {
+	s := ctx.Value(stateKey).(*sysbenchState)
+	return s
+}
+
+// Name implements Workloader interface
+func (w *Workloader) Name() string {
+	return "sysbench"
+}
+
+// InitThread implements Workloader interface
+func (w *Workloader) InitThread(ctx context.Context, threadID int) context.Context {
+	s := &sysbenchState{
+		TpcState: workload.NewTpcState(ctx, w.db),
+		stmts:    make(map[int]*tableStmts),
+	}
+	return context.WithValue(ctx, stateKey, s)
+}
+
+// CleanupThread implements Workloader interface
+func (w *Workloader) CleanupThread(ctx context.Context, threadID int) {
+	s := getState(ctx)
+	s.closeStmts()
+	if s.Conn != nil {
+		s.Conn.Close()
+	}
+}
+
+// Prepare creates the tables, then every thread loads its range of ids of every table, and
+// the secondary indexes are created after all the rows are loaded like sysbench.
+func (w *Workloader) Prepare(ctx context.Context, threadID int) error {
+	if threadID == 0 {
+		for table := 1; table <= w.cfg.Tables; table++ {
+			if err := w.createTable(ctx, table); err != nil {
+				return err
+			}
+		}
+	}
+	w.createTableWg.Done()
+	w.createTableWg.Wait()
+
+	start := w.cfg.TableSize*threadID/w.cfg.Threads + 1
+	end := w.cfg.TableSize * (threadID + 1) / w.cfg.Threads
+	for table := 1; table <= w.cfg.Tables; table++ {
+		if err := w.loadTable(ctx, table, start, end); err != nil {
+			return err
+		}
+	}
+	w.loadWg.Done()
+	w.loadWg.Wait()
+
+	for table := threadID + 1; table <= w.cfg.Tables; table += w.cfg.Threads {
+		if err := w.createIndexes(ctx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTable inserts the rows whose ids are in [start, end] into the table.
+func (w *Workloader) loadTable(ctx context.Context, table int, start, end int) error {
+	if start > end {
+		return nil
+	}
+	fmt.Printf("load to %s [%d, %d]\n", tableName(table), start, end)
+	s := getState(ctx)
+	l := sink.NewSQLSink(w.db, fmt.Sprintf("INSERT INTO %s (id, k, c, pad) VALUES ", tableName(table)), 3, time.Second)
+	for id := start; id <= end; id++ {
+		if err := l.WriteRow(ctx, id, w.randID(s.R), randC(s.R), randPad(s.R)); err != nil {
+			return err
+		}
+	}
+	return l.Flush(ctx)
+}
+
+// CheckPrepare checks that all the rows are loaded
+func (w *Workloader) CheckPrepare(ctx context.Context, threadID int) error {
+	if threadID != 0 {
+		return nil
+	}
+	s := getState(ctx)
+	for table := 1; table <= w.cfg.Tables; table++ {
+		var count int
+		if err := s.Conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+tableName(table)).Scan(&count); err != nil {
+			return err
+		}
+		if count != w.cfg.TableSize {
+			return fmt.Errorf("%s has %d rows, expect %d", tableName(table), count, w.cfg.TableSize)
+		}
+	}
+	return nil
+}
+
+func (w *Workloader) randID(r *rand.Rand) int {
+	return r.Intn(w.cfg.TableSize) + 1
+}
+
+// randDigits returns groups of 11 random digits separated by '-' like the c and pad of sysbench.
+func randDigits(r *rand.Rand, groups int) string {
+	b := make([]byte, 0, groups*12)
+	for i := 0; i < groups; i++ {
+		if i > 0 {
+			b = append(b, '-')
+		}
+		for j := 0; j < 11; j++ {
+			b = append(b, byte('0'+r.Intn(10)))
+		}
+	}
+	return string(b)
+}
+
+func randC(r *rand.Rand) string {
+	return randDigits(r, 10)
+}
+
+func randPad(r *rand.Rand) string {
+	return randDigits(r, 5)
+}
+
+func (w *Workloader) prepareStmts(ctx context.Context, s *sysbenchState, table int) (*tableStmts, error) {
+	if stmts, ok := s.stmts[table]; ok {
+		return stmts, nil
+	}
+	stmts := &tableStmts{}
+	name := tableName(table)
+	for _, p := range []struct {
+		stmt  **sql.Stmt
+		query string
+	}{
+		{&stmts.pointSelect, "SELECT c FROM %s WHERE id = ?"},
+		{&stmts.simpleRange, "SELECT c FROM %s WHERE id BETWEEN ? AND ?"},
+		{&stmts.sumRange, "SELECT SUM(k) FROM %s WHERE id BETWEEN ? AND ?"},
+		{&stmts.orderRange, "SELECT c FROM %s WHERE id BETWEEN ? AND ? ORDER BY c"},
+		{&stmts.distinctRange, "SELECT DISTINCT c FROM %s WHERE id BETWEEN ? AND ? ORDER BY c"},
+		{&stmts.indexUpdate, "UPDATE %s SET k = k + 1 WHERE id = ?"},
+		{&stmts.nonIndexUpdate, "UPDATE %s SET c = ? WHERE id = ?"},
+		{&stmts.delete, "DELETE FROM %s WHERE id = ?"},
+		{&stmts.insert, "INSERT INTO %s (id, k, c, pad) VALUES (?, ?, ?, ?)"},
+		{&stmts.insertAutoInc, "INSERT INTO %s (k, c, pad) VALUES (?, ?, ?)"},
+	} {
+		stmt, err := s.Conn.PrepareContext(ctx, convertToPQ(fmt.Sprintf(p.query, name), w.cfg.Driver))
+		if err != nil {
+			stmts.close()
+			return nil, err
+		}
+		*p.stmt = stmt
+	}
+	s.stmts[table] = stmts
+	return stmts, nil
+}
+
+// convertToPQ converts the placeholders to $1, $2... for postgres.
+func convertToPQ(query string, driver string) string {
+	if driver != "postgres" {
+		return query
+	}
+	var buf strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			buf.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
+
+// Run runs an event of the workload on a random table, in a transaction for the read only,
+// read write and write only workloads unless --skip-trx.
+func (w *Workloader) Run(ctx context.Context, threadID int) error {
+	s := getState(ctx)
+	if err := s.Conn.PingContext(ctx); err != nil {
+		if err := s.RefreshConn(ctx); err != nil {
+			return err
+		}
+		s.closeStmts()
+	}
+	table := s.R.Intn(w.cfg.Tables) + 1
+	if _, err := w.prepareStmts(ctx, s, table); err != nil {
+		return err
+	}
+
+	s.queries = 0
+	start := workload.StartTime(ctx)
+	err := w.runEvent(ctx, s, table)
+	w.measurement.Measure(w.cfg.Workload, time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("%s failed %v", w.cfg.Workload, err)
+	}
+	atomic.AddInt64(&w.queries, s.queries)
+	atomic.AddInt64(&w.events, 1)
+	return nil
+}
+
+func (w *Workloader) runEvent(ctx context.Context, s *sysbenchState, table int) error {
+	if !w.trx {
+		return w.event(ctx, s, table)
+	}
+	tx, err := s.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	s.tx = tx
+	defer func() {
+		s.tx = nil
+	}()
+	s.queries++
+	if err := w.event(ctx, s, table); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.queries++
+	return nil
+}
+
+func (w *Workloader) readOnly(ctx context.Context, s *sysbenchState, table int) error {
+	if err := w.pointSelects(ctx, s, table); err != nil {
+		return err
+	}
+	if w.cfg.RangeSelects {
+		return w.rangeSelects(ctx, s, table)
+	}
+	return nil
+}
+
+func (w *Workloader) readWrite(ctx context.Context, s *sysbenchState, table int) error {
+	if err := w.readOnly(ctx, s, table); err != nil {
+		return err
+	}
+	return w.writeOnly(ctx, s, table)
+}
+
+func (w *Workloader) writeOnly(ctx context.Context, s *sysbenchState, table int) error {
+	if err := w.indexUpdates(ctx, s, table); err != nil {
+		return err
+	}
+	stmts := s.stmts[table]
+	for i := 0; i < w.cfg.NonIndexUpdates; i++ {
+		if err := s.exec(ctx, stmts.nonIndexUpdate, randC(s.R), w.randID(s.R)); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < w.cfg.DeleteInserts; i++ {
+		id := w.randID(s.R)
+		if err := s.exec(ctx, stmts.delete, id); err != nil {
+			return err
+		}
+		if err := s.exec(ctx, stmts.insert, id, w.randID(s.R), randC(s.R), randPad(s.R)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Workloader) pointSelects(ctx context.Context, s *sysbenchState, table int) error {
+	stmts := s.stmts[table]
+	for i := 0; i < w.cfg.PointSelects; i++ {
+		if err := s.query(ctx, stmts.pointSelect, w.randID(s.R)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Workloader) rangeSelects(ctx context.Context, s *sysbenchState, table int) error {
+	stmts := s.stmts[table]
+	for _, r := range []struct {
+		stmt  *sql.Stmt
+		count int
+	}{
+		{stmts.simpleRange, w.cfg.SimpleRanges},
+		{stmts.sumRange, w.cfg.SumRanges},
+		{stmts.orderRange, w.cfg.OrderRanges},
+		{stmts.distinctRange, w.cfg.DistinctRanges},
+	} {
+		for i := 0; i < r.count; i++ {
+			start := w.randID(s.R)
+			if err := s.query(ctx, r.stmt, start, start+w.cfg.RangeSize-1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *Workloader) indexUpdates(ctx context.Context, s *sysbenchState, table int) error {
+	stmts := s.stmts[table]
+	for i := 0; i < w.cfg.IndexUpdates; i++ {
+		if err := s.exec(ctx, stmts.indexUpdate, w.randID(s.R)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insert inserts a row with the id assigned by auto increment, or a new id after the loaded
+// ones without --auto-inc.
+func (w *Workloader) insert(ctx context.Context, s *sysbenchState, table int) error {
+	stmts := s.stmts[table]
+	if w.cfg.AutoInc {
+		return s.exec(ctx, stmts.insertAutoInc, w.randID(s.R), randC(s.R), randPad(s.R))
+	}
+	id := atomic.AddInt64(&w.insertSeq, 1)
+	return s.exec(ctx, stmts.insert, id, w.randID(s.R), randC(s.R), randPad(s.R))
+}
+
+// Cleanup implements Workloader interface
+func (w *Workloader) Cleanup(ctx context.Context, threadID int) error {
+	if threadID != 0 {
+		return nil
+	}
+	return w.dropTables(ctx)
+}
+
+// Check implements Workloader interface
+func (w *Workloader) Check(ctx context.Context, threadID int) error {
+	return nil
+}
+
+func outputRtMeasurement(outputStyle string, prefix string, opMeasurement map[string]*measurement.Histogram) {
+	keys := make([]string, 0, len(opMeasurement))
+	for k := range opMeasurement {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := [][]string{}
+	for _, op := range keys {
+		hist := opMeasurement[op]
+		if !hist.Empty() {
+			line := []string{prefix, strings.ToUpper(op)}
+			line = append(line, hist.Summary()...)
+			lines = append(lines, line)
+		}
+	}
+	headers := []string{"Prefix", "Operation", "Takes(s)", "Count", "TPM", "Sum(ms)", "Avg(ms)", "50th(ms)", "90th(ms)", "95th(ms)", "99th(ms)", "99.9th(ms)", "Max(ms)"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", headers, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+
+	errLines := measurement.ErrorClassLines(prefix, opMeasurement)
+	if len(errLines) == 0 {
+		return
+	}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", measurement.ErrorClassHeaders, errLines)
+	case util.OutputStyleTable:
+		util.RenderTable(measurement.ErrorClassHeaders, errLines)
+	case util.OutputStyleJson:
+		util.RenderJson(measurement.ErrorClassHeaders, errLines)
+	}
+}
+
+// OutputStats implements Workloader interface
+func (w *Workloader) OutputStats(ifSummaryReport bool) {
+	w.measurement.Output(ifSummaryReport, w.cfg.OutputStyle, outputRtMeasurement)
+	if !ifSummaryReport {
+		return
+	}
+	if results := w.Results(); results != nil {
+		lines := [][]string{{util.FloatToOneString(results["tps"]), util.FloatToOneString(results["qps"])}}
+		switch w.cfg.OutputStyle {
+		case util.OutputStylePlain:
+			util.RenderString("tps: %s, qps: %s\n", nil, lines)
+		case util.OutputStyleTable:
+			util.RenderTable([]string{"tps", "qps"}, lines)
+		case util.OutputStyleJson:
+			util.RenderJson([]string{"tps", "qps"}, lines)
+		}
+	}
+}
+
+// Results implements workload.ResultProvider, it returns the events per second as tps and the
+// queries of the events per second as qps, or nil if no event is measured.
+func (w *Workloader) Results() map[string]float64 {
+	hist, ok := w.measurement.OpSumMeasurement[w.cfg.Workload]
+	events := atomic.LoadInt64(&w.events)
+	if !ok || hist.Empty() || events == 0 {
+		return nil
+	}
+	tps := hist.GetInfo().Ops
+	return map[string]float64{
+		"tps": tps,
+		"qps": tps * float64(atomic.LoadInt64(&w.queries)) / float64(events),
+	}
+}
+
+// Measurement returns the response time measurement of the workload.
+func (w *Workloader) Measurement() *measurement.Measurement {
+	return w.measurement
+}
+
+// DBName returns the name of test db.
+func (w *Workloader) DBName() string {
+	return w.cfg.DBName
+}
+
+func (w *Workloader) IsPlanReplayerDumpEnabled() bool {
+	return false
+}
+
+func (w *Workloader) PreparePlanReplayerDump() error {
+	return nil
+}
+
+func (w *Workloader) FinishPlanReplayerDump() error {
+	return nil
+}
+
+func (w *Workloader) Exec(sql string) error {
+	return nil
+}
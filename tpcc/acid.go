@@ -0,0 +1,865 @@
+package tpcc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+)
+
+const (
+	acidSelectItemPrice      = `SELECT i_price FROM item WHERE i_id = ?`
+	acidUpdateItemPrice      = `UPDATE item SET i_price = i_price + ? WHERE i_id = ?`
+	acidSelectStock          = `SELECT s_quantity, s_dist_%02d FROM stock WHERE s_w_id = ? AND s_i_id = ? FOR UPDATE`
+	acidInsertOrderLine      = `INSERT INTO order_line (ol_o_id, ol_d_id, ol_w_id, ol_number, ol_i_id, ol_supply_w_id, ol_quantity, ol_amount, ol_dist_info) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	acidSelectOldestNewOrder = `SELECT no_o_id, o_c_id FROM new_order, orders WHERE no_w_id = ? AND no_d_id = ? AND o_w_id = no_w_id AND o_d_id = no_d_id AND o_id = no_o_id ORDER BY no_o_id ASC LIMIT 1`
+	acidDeleteNewOrder       = `DELETE FROM new_order WHERE no_w_id = ? AND no_d_id = ? AND no_o_id = ?`
+	acidUpdateOrder          = `UPDATE orders SET o_carrier_id = ? WHERE o_w_id = ? AND o_d_id = ? AND o_id = ?`
+	acidSelectOrder          = `SELECT o_c_id FROM orders WHERE o_w_id = ? AND o_d_id = ? AND o_id = ?`
+	acidUpdateOrderLine      = `UPDATE order_line SET ol_delivery_d = ? WHERE ol_w_id = ? AND ol_d_id = ? AND ol_o_id = ?`
+	acidSelectSumAmount      = `SELECT SUM(ol_amount) FROM order_line WHERE ol_w_id = ? AND ol_d_id = ? AND ol_o_id = ?`
+	acidSelectWarehouse      = `SELECT w_ytd FROM warehouse WHERE w_id = ?`
+	acidSelectDistrict       = `SELECT d_ytd FROM district WHERE d_w_id = ? AND d_id = ?`
+	acidSelectCustomer       = `SELECT c_balance, c_ytd_payment, c_payment_cnt FROM customer WHERE c_w_id = ? AND c_d_id = ? AND c_id = ?`
+	acidCountHistory         = `SELECT count(*) FROM history WHERE h_c_w_id = ? AND h_c_d_id = ? AND h_c_id = ?`
+	acidCountOrders          = `SELECT count(*) FROM orders WHERE o_w_id = ? AND o_d_id = ? AND o_c_id = ?`
+	acidCountNewOrders       = `SELECT count(*) FROM new_order WHERE no_w_id = ? AND no_d_id = ?`
+	acidCountOrder           = `SELECT count(*) FROM orders WHERE o_w_id = ? AND o_d_id = ? AND o_id = ?`
+	acidCountOrderLines      = `SELECT count(*) FROM order_line WHERE ol_w_id = ? AND ol_d_id = ? AND ol_o_id = ?`
+)
+
+const (
+	// acidOrderLines is the number of order lines of the new orders of the ACID tests
+	acidOrderLines = 5
+	// acidDurabilityTxns is the number of the new order and payment transactions committed before
+	// the durability verification
+	acidDurabilityTxns = 10
+	// DefaultACIDBlockWait is the time to wait before a transaction is regarded as blocked by
+	// the other one in the isolation tests
+	DefaultACIDBlockWait = 2 * time.Second
+)
+
+type acidTest struct {
+	name string
+	desc string
+	// run returns a note of how the test passed, or an error if it failed
+	run func(ctx context.Context, a *acidRunner) (string, error)
+}
+
+// acidTests are the atomicity tests of 3.2.2, the isolation tests of 3.4.2 and a durability
+// verification like 3.5.4, in running order.
+var acidTests = []acidTest{
+	{name: "atomicity-1", desc: "Committed Payment is fully applied",
+		run: func(ctx context.Context, a *acidRunner) (string, error) { return a.atomicity(ctx, true) }},
+	{name: "atomicity-2", desc: "Rolled back Payment is not applied",
+		run: func(ctx context.Context, a *acidRunner) (string, error) { return a.atomicity(ctx, false) }},
+	{name: "isolation-1", desc: "Order-Status with committed New-Order of the customer",
+		run: func(ctx context.Context, a *acidRunner) (string, error) {
+			return a.isolationNewOrderOrderStatus(ctx, true)
+		}},
+	{name: "isolation-2", desc: "Order-Status with rolled back New-Order of the customer",
+		run: func(ctx context.Context, a *acidRunner) (string, error) {
+			return a.isolationNewOrderOrderStatus(ctx, false)
+		}},
+	{name: "isolation-3", desc: "New-Order with committed New-Order of the district",
+		run: func(ctx context.Context, a *acidRunner) (string, error) { return a.isolationNewOrders(ctx, true) }},
+	{name: "isolation-4", desc: "New-Order with rolled back New-Order of the district",
+		run: func(ctx context.Context, a *acidRunner) (string, error) { return a.isolationNewOrders(ctx, false) }},
+	{name: "isolation-5", desc: "Payment with committed Delivery of the customer",
+		run: func(ctx context.Context, a *acidRunner) (string, error) { return a.isolationDeliveryPayment(ctx, true) }},
+	{name: "isolation-6", desc: "Payment with rolled back Delivery of the customer",
+		run: func(ctx context.Context, a *acidRunner) (string, error) {
+			return a.isolationDeliveryPayment(ctx, false)
+		}},
+	{name: "isolation-7", desc: "Repeatable read of item prices updated by another transaction",
+		run: func(ctx context.Context, a *acidRunner) (string, error) { return a.isolationItemPrice(ctx) }},
+	{name: "isolation-8", desc: "No phantom in orders of a customer inserted by New-Order",
+		run: func(ctx context.Context, a *acidRunner) (string, error) {
+			return a.isolationPhantom(ctx, acidCountOrders, true)
+		}},
+	{name: "isolation-9", desc: "No phantom in new orders of a district inserted by New-Order",
+		run: func(ctx context.Context, a *acidRunner) (string, error) {
+			return a.isolationPhantom(ctx, acidCountNewOrders, false)
+		}},
+	{name: "durability", desc: "Committed New-Order and Payment survive and keep consistency",
+		run: func(ctx context.Context, a *acidRunner) (string, error) { return a.durability(ctx) }},
+}
+
+// selectACIDTests returns the tests of the names in running order, or all tests if no name is given.
+func selectACIDTests(names []string) ([]acidTest, error) {
+	if len(names) == 0 {
+		return acidTests, nil
+	}
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			return acidTests, nil
+		}
+		found := false
+		for _, t := range acidTests {
+			if t.name == name || strings.HasPrefix(t.name, name+"-") {
+				selected[t.name] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown acid test %s", name)
+		}
+	}
+	var tests []acidTest
+	for _, t := range acidTests {
+		if selected[t.name] {
+			tests = append(tests, t)
+		}
+	}
+	return tests, nil
+}
+
+// RunACID runs the ACID tests on dedicated connections and reports pass/fail per test, it returns
+// whether all tests passed. The tests expect no other workload running on the database.
+func (w *Workloader) RunACID(ctx context.Context) (bool, error) {
+	tests, err := selectACIDTests(w.cfg.ACIDTests)
+	if err != nil {
+		return false, err
+	}
+	a := &acidRunner{
+		w:         w,
+		r:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		blockWait: w.cfg.ACIDBlockWait,
+	}
+	if a.blockWait <= 0 {
+		a.blockWait = DefaultACIDBlockWait
+	}
+
+	passed := true
+	lines := make([][]string, 0, len(tests))
+	for _, t := range tests {
+		fmt.Printf("begin to run acid test %s: %s\n", t.name, t.desc)
+		note, err := t.run(ctx, a)
+		result := "PASS"
+		if err != nil {
+			result, note = "FAIL", err.Error()
+			passed = false
+		}
+		fmt.Printf("acid test %s %s %s\n", t.name, result, note)
+		lines = append(lines, []string{t.name, t.desc, result, note})
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	outputACIDResults(w.cfg.OutputStyle, lines)
+	return passed, nil
+}
+
+func outputACIDResults(outputStyle string, lines [][]string) {
+	headers := []string{"Test", "Description", "Result", "Note"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("[ACID] %s - %s: %s %s\n", nil, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+}
+
+// acidQuerier is implemented by both *sql.DB for verification and *sql.Tx for the tested transactions.
+type acidQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// acidTxn is a transaction on its dedicated connection.
+type acidTxn struct {
+	conn *sql.Conn
+	tx   *sql.Tx
+}
+
+// end commits or rolls back the transaction.
+func (t *acidTxn) end(commit bool) error {
+	if commit {
+		return t.tx.Commit()
+	}
+	return t.tx.Rollback()
+}
+
+// close rolls back the transaction if it is not ended and releases the connection.
+func (t *acidTxn) close() {
+	if t == nil {
+		return
+	}
+	t.tx.Rollback()
+	t.conn.Close()
+}
+
+// acidPending is a statement sequence of a transaction running in the background, which may be
+// blocked by the locks of the other transaction.
+type acidPending struct {
+	done     chan error
+	finished bool
+	err      error
+}
+
+func runPending(f func() error) *acidPending {
+	p := &acidPending{done: make(chan error, 1)}
+	go func() {
+		p.done <- f()
+	}()
+	return p
+}
+
+// blocked returns whether the pending statements are not finished in the wait time.
+func (p *acidPending) blocked(wait time.Duration) bool {
+	select {
+	case p.err = <-p.done:
+		p.finished = true
+		return false
+	case <-time.After(wait):
+		return true
+	}
+}
+
+func (p *acidPending) wait() error {
+	if !p.finished {
+		p.err = <-p.done
+		p.finished = true
+	}
+	return p.err
+}
+
+type acidRunner struct {
+	w         *Workloader
+	r         *rand.Rand
+	blockWait time.Duration
+}
+
+func (a *acidRunner) begin(ctx context.Context) (*acidTxn, error) {
+	conn, err := a.w.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.IsolationLevel(a.w.cfg.Isolation),
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &acidTxn{conn: conn, tx: tx}, nil
+}
+
+func (a *acidRunner) exec(ctx context.Context, q acidQuerier, query string, args ...interface{}) error {
+	if _, err := q.ExecContext(ctx, convertToPQ(query, a.w.cfg.Driver), args...); err != nil {
+		return fmt.Errorf("exec %s failed %w", query, err)
+	}
+	return nil
+}
+
+func (a *acidRunner) queryRow(ctx context.Context, q acidQuerier, query string, args []interface{}, dest ...interface{}) error {
+	if err := q.QueryRowContext(ctx, convertToPQ(query, a.w.cfg.Driver), args...).Scan(dest...); err != nil {
+		return fmt.Errorf("query %s failed %w", query, err)
+	}
+	return nil
+}
+
+func isConflict(err error) bool {
+	return util.ClassifyError(err) == util.ErrClassConflict
+}
+
+func floatEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+func (a *acidRunner) randWarehouse() int {
+	return randInt(a.r, 1, a.w.cfg.Warehouses)
+}
+
+func (a *acidRunner) randDistrict() int {
+	return randInt(a.r, 1, districtPerWarehouse)
+}
+
+// randItems returns distinct items of a new order.
+func (a *acidRunner) randItems() []int {
+	items := make([]int, 0, acidOrderLines)
+	for len(items) < acidOrderLines {
+		item := randItemID(a.r)
+		duplicated := false
+		for _, i := range items {
+			duplicated = duplicated || i == item
+		}
+		if !duplicated {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// newOrder executes a New-Order of local items in the transaction and returns the order id, refer 2.4.2.2.
+func (a *acidRunner) newOrder(ctx context.Context, q acidQuerier, wID, dID, cID int, items []int) (int, error) {
+	var (
+		oID  int
+		dTax float64
+	)
+	if err := a.queryRow(ctx, q, newOrderSelectDistrict, []interface{}{dID, wID}, &oID, &dTax); err != nil {
+		return 0, err
+	}
+	if err := a.exec(ctx, q, newOrderUpdateDistrict, oID, dID, wID); err != nil {
+		return 0, err
+	}
+	if err := a.exec(ctx, q, newOrderInsertOrder, oID, dID, wID, cID, time.Now().Format(timeFormat), len(items), 1); err != nil {
+		return 0, err
+	}
+	if err := a.exec(ctx, q, newOrderInsertNewOrder, oID, dID, wID); err != nil {
+		return 0, err
+	}
+	const olQuantity = 5
+	for i, item := range items {
+		var (
+			price    float64
+			quantity int
+			distInfo string
+		)
+		if err := a.queryRow(ctx, q, acidSelectItemPrice, []interface{}{item}, &price); err != nil {
+			return 0, err
+		}
+		if err := a.queryRow(ctx, q, fmt.Sprintf(acidSelectStock, dID), []interface{}{wID, item}, &quantity, &distInfo); err != nil {
+			return 0, err
+		}
+		if quantity >= olQuantity+10 {
+			quantity -= olQuantity
+		} else {
+			quantity += 91 - olQuantity
+		}
+		if err := a.exec(ctx, q, newOrderUpdateStock, quantity, olQuantity, 0, item, wID); err != nil {
+			return 0, err
+		}
+		if err := a.exec(ctx, q, acidInsertOrderLine, oID, dID, wID, i+1, item, wID, olQuantity, price*olQuantity, distInfo); err != nil {
+			return 0, err
+		}
+	}
+	return oID, nil
+}
+
+// commitNewOrder executes and commits a New-Order on a dedicated connection.
+func (a *acidRunner) commitNewOrder(ctx context.Context, wID, dID, cID int) (int, error) {
+	t, err := a.begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer t.close()
+	oID, err := a.newOrder(ctx, t.tx, wID, dID, cID, a.randItems())
+	if err != nil {
+		return 0, err
+	}
+	return oID, t.end(true)
+}
+
+// payment executes a Payment of the customer by id in the transaction, refer 2.5.2.2.
+func (a *acidRunner) payment(ctx context.Context, q acidQuerier, wID, dID, cID int, amount float64) error {
+	if err := a.exec(ctx, q, paymentUpdateWarehouse, amount, wID); err != nil {
+		return err
+	}
+	if err := a.exec(ctx, q, paymentUpdateDistrict, amount, wID, dID); err != nil {
+		return err
+	}
+	if err := a.exec(ctx, q, paymentUpdateCustomer, amount, amount, wID, dID, cID); err != nil {
+		return err
+	}
+	return a.exec(ctx, q, paymentInsertHistory, dID, wID, cID, dID, wID, time.Now().Format(timeFormat), amount, "acid")
+}
+
+func (a *acidRunner) randAmount() float64 {
+	return float64(randInt(a.r, 100, 500000)) / float64(100.0)
+}
+
+// latestOrder returns the latest order of the customer like Order-Status, or 0 if there is none, refer 2.6.2.2.
+func (a *acidRunner) latestOrder(ctx context.Context, q acidQuerier, wID, dID, cID int) (int, error) {
+	var (
+		oID        int
+		oCarrierID sql.NullInt64
+		oEntryD    string
+	)
+	err := q.QueryRowContext(ctx, convertToPQ(orderStatusSelectLatestOrder, a.w.cfg.Driver), wID, dID, cID).Scan(&oID, &oCarrierID, &oEntryD)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("query %s failed %w", orderStatusSelectLatestOrder, err)
+	}
+	return oID, nil
+}
+
+// deliverDistrict delivers the oldest new order of the district in the transaction, and returns
+// the order, its customer and amount, refer 2.7.4.2.
+func (a *acidRunner) deliverDistrict(ctx context.Context, q acidQuerier, wID, dID int) (oID, cID int, amount float64, err error) {
+	if err = a.queryRow(ctx, q, deliverySelectNewOrder, []interface{}{wID, dID}, &oID); err != nil {
+		return
+	}
+	if err = a.exec(ctx, q, acidDeleteNewOrder, wID, dID, oID); err != nil {
+		return
+	}
+	if err = a.exec(ctx, q, acidUpdateOrder, randInt(a.r, 1, 10), wID, dID, oID); err != nil {
+		return
+	}
+	if err = a.queryRow(ctx, q, acidSelectOrder, []interface{}{wID, dID, oID}, &cID); err != nil {
+		return
+	}
+	if err = a.exec(ctx, q, acidUpdateOrderLine, time.Now().Format(timeFormat), wID, dID, oID); err != nil {
+		return
+	}
+	if err = a.queryRow(ctx, q, acidSelectSumAmount, []interface{}{wID, dID, oID}, &amount); err != nil {
+		return
+	}
+	err = a.exec(ctx, q, deliveryUpdateCustomer, amount, wID, dID, cID)
+	return
+}
+
+type acidCustomer struct {
+	balance    float64
+	ytdPayment float64
+	paymentCnt int
+}
+
+func (a *acidRunner) customer(ctx context.Context, q acidQuerier, wID, dID, cID int) (c acidCustomer, err error) {
+	err = a.queryRow(ctx, q, acidSelectCustomer, []interface{}{wID, dID, cID}, &c.balance, &c.ytdPayment, &c.paymentCnt)
+	return
+}
+
+// paymentSnapshot is the committed state updated by a Payment.
+type paymentSnapshot struct {
+	wYtd     float64
+	dYtd     float64
+	customer acidCustomer
+	history  int
+}
+
+func (a *acidRunner) paymentSnapshot(ctx context.Context, wID, dID, cID int) (s paymentSnapshot, err error) {
+	if err = a.queryRow(ctx, a.w.db, acidSelectWarehouse, []interface{}{wID}, &s.wYtd); err != nil {
+		return
+	}
+	if err = a.queryRow(ctx, a.w.db, acidSelectDistrict, []interface{}{wID, dID}, &s.dYtd); err != nil {
+		return
+	}
+	if s.customer, err = a.customer(ctx, a.w.db, wID, dID, cID); err != nil {
+		return
+	}
+	err = a.queryRow(ctx, a.w.db, acidCountHistory, []interface{}{wID, dID, cID}, &s.history)
+	return
+}
+
+// atomicity verifies that all or none of the updates of a committed or rolled back Payment are
+// applied, refer 3.2.2.1 and 3.2.2.2.
+func (a *acidRunner) atomicity(ctx context.Context, commit bool) (string, error) {
+	wID, dID, cID, amount := a.randWarehouse(), a.randDistrict(), randCustomerID(a.r), a.randAmount()
+	before, err := a.paymentSnapshot(ctx, wID, dID, cID)
+	if err != nil {
+		return "", err
+	}
+
+	t, err := a.begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer t.close()
+	if err := a.payment(ctx, t.tx, wID, dID, cID, amount); err != nil {
+		return "", err
+	}
+	if err := t.end(commit); err != nil {
+		return "", err
+	}
+
+	after, err := a.paymentSnapshot(ctx, wID, dID, cID)
+	if err != nil {
+		return "", err
+	}
+	expected := before
+	if commit {
+		expected.wYtd += amount
+		expected.dYtd += amount
+		expected.customer.balance -= amount
+		expected.customer.ytdPayment += amount
+		expected.customer.paymentCnt++
+		expected.history++
+	}
+	if !floatEqual(after.wYtd, expected.wYtd) || !floatEqual(after.dYtd, expected.dYtd) ||
+		!floatEqual(after.customer.balance, expected.customer.balance) ||
+		!floatEqual(after.customer.ytdPayment, expected.customer.ytdPayment) ||
+		after.customer.paymentCnt != expected.customer.paymentCnt || after.history != expected.history {
+		return "", fmt.Errorf("payment of %.2f to customer (%d, %d, %d) expects %+v, but got %+v", amount, wID, dID, cID, expected, after)
+	}
+	return fmt.Sprintf("customer (%d, %d, %d)", wID, dID, cID), nil
+}
+
+// blockedNote describes how the second transaction T2 interleaved with the first one T1.
+func blockedNote(blocked bool) string {
+	if blocked {
+		return "T2 blocked until T1 ended"
+	}
+	return "T2 not blocked"
+}
+
+// isolationNewOrderOrderStatus runs an Order-Status T2 for the customer of an uncommitted
+// New-Order T1, T2 must not see the order of T1 before it is committed, refer 3.4.2.1 and 3.4.2.2.
+func (a *acidRunner) isolationNewOrderOrderStatus(ctx context.Context, commit bool) (string, error) {
+	wID, dID, cID := a.randWarehouse(), a.randDistrict(), randCustomerID(a.r)
+	prevOID, err := a.latestOrder(ctx, a.w.db, wID, dID, cID)
+	if err != nil {
+		return "", err
+	}
+
+	var t1, t2 *acidTxn
+	defer func() {
+		t1.close()
+		t2.close()
+	}()
+	if t1, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	oID, err := a.newOrder(ctx, t1.tx, wID, dID, cID, a.randItems())
+	if err != nil {
+		return "", err
+	}
+	if t2, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	var readOID int
+	p := runPending(func() (err error) {
+		readOID, err = a.latestOrder(ctx, t2.tx, wID, dID, cID)
+		return
+	})
+	blocked := p.blocked(a.blockWait)
+	if err := t1.end(commit); err != nil {
+		return "", err
+	}
+	if err := p.wait(); err != nil {
+		if isConflict(err) {
+			return "T2 aborted", nil
+		}
+		return "", err
+	}
+	switch {
+	case readOID == prevOID:
+	case readOID == oID && commit && blocked:
+	default:
+		return "", fmt.Errorf("T2 read order %d of customer (%d, %d, %d), but the latest committed order is %d and T1 inserted order %d",
+			readOID, wID, dID, cID, prevOID, oID)
+	}
+	return blockedNote(blocked), nil
+}
+
+// isolationNewOrders runs a New-Order T2 on the district of an uncommitted New-Order T1, T2 must
+// get the next order id after T1 commits or the same one after T1 rolls back, refer 3.4.2.3 and 3.4.2.4.
+func (a *acidRunner) isolationNewOrders(ctx context.Context, commit bool) (string, error) {
+	wID, dID := a.randWarehouse(), a.randDistrict()
+
+	var (
+		t1, t2 *acidTxn
+		err    error
+	)
+	defer func() {
+		t1.close()
+		t2.close()
+	}()
+	if t1, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	oID1, err := a.newOrder(ctx, t1.tx, wID, dID, randCustomerID(a.r), a.randItems())
+	if err != nil {
+		return "", err
+	}
+	if t2, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	var oID2 int
+	cID2, items2 := randCustomerID(a.r), a.randItems()
+	p := runPending(func() (err error) {
+		oID2, err = a.newOrder(ctx, t2.tx, wID, dID, cID2, items2)
+		return
+	})
+	blocked := p.blocked(a.blockWait)
+	if err := t1.end(commit); err != nil {
+		return "", err
+	}
+	err = p.wait()
+	if err == nil {
+		err = t2.end(true)
+	}
+	if err != nil {
+		if isConflict(err) {
+			return "T2 aborted", nil
+		}
+		return "", err
+	}
+	expected := oID1
+	if commit {
+		expected++
+	}
+	if oID2 != expected {
+		return "", fmt.Errorf("T2 got order id %d of district (%d, %d), but expects %d after T1 got %d", oID2, wID, dID, expected, oID1)
+	}
+	return blockedNote(blocked), nil
+}
+
+// isolationDeliveryPayment runs a Payment T2 for the customer of an uncommitted Delivery T1, the
+// balance of the customer must reflect both if T1 commits or only T2 if it rolls back, refer
+// 3.4.2.5 and 3.4.2.6.
+func (a *acidRunner) isolationDeliveryPayment(ctx context.Context, commit bool) (string, error) {
+	wID, dID := a.randWarehouse(), a.randDistrict()
+	// make sure there is an undelivered order in the district
+	if _, err := a.commitNewOrder(ctx, wID, dID, randCustomerID(a.r)); err != nil {
+		return "", err
+	}
+	var oID, cID int
+	if err := a.queryRow(ctx, a.w.db, acidSelectOldestNewOrder, []interface{}{wID, dID}, &oID, &cID); err != nil {
+		return "", err
+	}
+	before, err := a.customer(ctx, a.w.db, wID, dID, cID)
+	if err != nil {
+		return "", err
+	}
+
+	var t1, t2 *acidTxn
+	defer func() {
+		t1.close()
+		t2.close()
+	}()
+	if t1, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	deliveredOID, deliveredCID, amount, err := a.deliverDistrict(ctx, t1.tx, wID, dID)
+	if err != nil {
+		return "", err
+	}
+	if deliveredOID != oID || deliveredCID != cID {
+		return "", fmt.Errorf("T1 delivered order %d of customer %d, but the oldest new order is %d of customer %d, is there another workload running",
+			deliveredOID, deliveredCID, oID, cID)
+	}
+	if t2, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	payment := a.randAmount()
+	p := runPending(func() error {
+		return a.payment(ctx, t2.tx, wID, dID, cID, payment)
+	})
+	blocked := p.blocked(a.blockWait)
+	if err := t1.end(commit); err != nil {
+		return "", err
+	}
+	err = p.wait()
+	if err == nil {
+		err = t2.end(true)
+	}
+	note := blockedNote(blocked)
+	if err != nil {
+		if !isConflict(err) {
+			return "", err
+		}
+		payment, note = 0, "T2 aborted"
+	}
+
+	after, err := a.customer(ctx, a.w.db, wID, dID, cID)
+	if err != nil {
+		return "", err
+	}
+	expected := before.balance - payment
+	if commit {
+		expected += amount
+	}
+	if !floatEqual(after.balance, expected) {
+		return "", fmt.Errorf("balance of customer (%d, %d, %d) expects %.2f after delivery of %.2f and payment of %.2f from %.2f, but got %.2f",
+			wID, dID, cID, expected, amount, payment, before.balance, after.balance)
+	}
+	return note, nil
+}
+
+// isolationItemPrice updates the price of an item read by T1 in a committed T2, T1 must read the
+// same price again, refer 3.4.2.7.
+func (a *acidRunner) isolationItemPrice(ctx context.Context) (string, error) {
+	item := randItemID(a.r)
+
+	var (
+		t1, t2 *acidTxn
+		err    error
+	)
+	defer func() {
+		t1.close()
+		t2.close()
+	}()
+	if t1, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	var price, reread float64
+	if err := a.queryRow(ctx, t1.tx, acidSelectItemPrice, []interface{}{item}, &price); err != nil {
+		return "", err
+	}
+	if t2, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	p := runPending(func() error {
+		if err := a.exec(ctx, t2.tx, acidUpdateItemPrice, 1, item); err != nil {
+			return err
+		}
+		return t2.end(true)
+	})
+	blocked := p.blocked(a.blockWait)
+	readErr := a.queryRow(ctx, t1.tx, acidSelectItemPrice, []interface{}{item}, &reread)
+	if err := t1.end(true); err != nil && readErr == nil {
+		readErr = err
+	}
+	updateErr := p.wait()
+	if updateErr == nil {
+		// restore the price updated by T2
+		if err := a.exec(ctx, a.w.db, acidUpdateItemPrice, -1, item); err != nil {
+			return "", err
+		}
+	} else if !isConflict(updateErr) {
+		return "", updateErr
+	}
+	if readErr != nil {
+		if isConflict(readErr) {
+			return "T1 aborted", nil
+		}
+		return "", readErr
+	}
+	if !floatEqual(price, reread) {
+		return "", fmt.Errorf("T1 read price %.2f of item %d, but re-read %.2f after T2 updated it", price, item, reread)
+	}
+	return blockedNote(blocked), nil
+}
+
+// isolationPhantom inserts a New-Order in a committed T2 while T1 reads the orders of a customer
+// or the new orders of a district, T1 must read the same count again, refer 3.4.2.8 and 3.4.2.9.
+func (a *acidRunner) isolationPhantom(ctx context.Context, countQuery string, byCustomer bool) (string, error) {
+	wID, dID, cID := a.randWarehouse(), a.randDistrict(), randCustomerID(a.r)
+	args := []interface{}{wID, dID}
+	if byCustomer {
+		args = append(args, cID)
+	}
+
+	var (
+		t1, t2 *acidTxn
+		err    error
+	)
+	defer func() {
+		t1.close()
+		t2.close()
+	}()
+	if t1, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	var count, recount int
+	if err := a.queryRow(ctx, t1.tx, countQuery, args, &count); err != nil {
+		return "", err
+	}
+	if t2, err = a.begin(ctx); err != nil {
+		return "", err
+	}
+	items := a.randItems()
+	p := runPending(func() error {
+		if _, err := a.newOrder(ctx, t2.tx, wID, dID, cID, items); err != nil {
+			return err
+		}
+		return t2.end(true)
+	})
+	blocked := p.blocked(a.blockWait)
+	readErr := a.queryRow(ctx, t1.tx, countQuery, args, &recount)
+	if err := t1.end(true); err != nil && readErr == nil {
+		readErr = err
+	}
+	if err := p.wait(); err != nil && !isConflict(err) {
+		return "", err
+	}
+	if readErr != nil {
+		if isConflict(readErr) {
+			return "T1 aborted", nil
+		}
+		return "", readErr
+	}
+	if count != recount {
+		return "", fmt.Errorf("T1 read %d rows, but re-read %d rows after T2 inserted a new order to district (%d, %d)", count, recount, wID, dID)
+	}
+	return blockedNote(blocked), nil
+}
+
+// durability commits New-Orders and Payments on dedicated connections and verifies them on new
+// connections after the pause, in which a failure of 3.5.3 can be injected externally, e.g. by
+// killing or restarting the database, refer 3.5.4.
+func (a *acidRunner) durability(ctx context.Context) (string, error) {
+	type order struct{ wID, dID, oID int }
+
+	wID := a.randWarehouse()
+	var before float64
+	if err := a.queryRow(ctx, a.w.db, acidSelectWarehouse, []interface{}{wID}, &before); err != nil {
+		return "", err
+	}
+	orders := make([]order, 0, acidDurabilityTxns)
+	var total float64
+	for i := 0; i < acidDurabilityTxns; i++ {
+		dID, cID, amount := a.randDistrict(), randCustomerID(a.r), a.randAmount()
+		oID, err := a.commitNewOrder(ctx, wID, dID, cID)
+		if err != nil {
+			return "", err
+		}
+		orders = append(orders, order{wID, dID, oID})
+
+		t, err := a.begin(ctx)
+		if err != nil {
+			return "", err
+		}
+		err = a.payment(ctx, t.tx, wID, dID, cID, amount)
+		if err == nil {
+			err = t.end(true)
+		}
+		t.close()
+		if err != nil {
+			return "", err
+		}
+		total += amount
+	}
+
+	if pause := a.w.cfg.ACIDDurabilityPause; pause > 0 {
+		fmt.Printf("%d new orders and payments committed to warehouse %d, inject the failure in %s\n", acidDurabilityTxns, wID, pause)
+		select {
+		case <-time.After(pause):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	for _, o := range orders {
+		var orderCnt, lineCnt int
+		if err := a.queryRow(ctx, a.w.db, acidCountOrder, []interface{}{o.wID, o.dID, o.oID}, &orderCnt); err != nil {
+			return "", err
+		}
+		if err := a.queryRow(ctx, a.w.db, acidCountOrderLines, []interface{}{o.wID, o.dID, o.oID}, &lineCnt); err != nil {
+			return "", err
+		}
+		if orderCnt != 1 || lineCnt != acidOrderLines {
+			return "", fmt.Errorf("committed order (%d, %d, %d) has %d rows and %d order lines", o.wID, o.dID, o.oID, orderCnt, lineCnt)
+		}
+	}
+	var after float64
+	if err := a.queryRow(ctx, a.w.db, acidSelectWarehouse, []interface{}{wID}, &after); err != nil {
+		return "", err
+	}
+	if !floatEqual(after, before+total) {
+		return "", fmt.Errorf("w_ytd of warehouse %d expects %.2f after committed payments of %.2f, but got %.2f", wID, before+total, total, after)
+	}
+
+	s := &tpccState{TpcState: workload.NewTpcState(ctx, a.w.db)}
+	defer s.Conn.Close()
+	checkCtx := context.WithValue(ctx, stateKey, s)
+	for _, check := range []func(ctx context.Context, warehouse int) error{
+		a.w.checkCondition1, a.w.checkCondition2, a.w.checkCondition3, a.w.checkCondition4,
+	} {
+		if err := check(checkCtx, wID); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%d new orders and payments of warehouse %d", acidDurabilityTxns, wID), nil
+}
@@ -0,0 +1,54 @@
+package tpcc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectACIDTests(t *testing.T) {
+	names := func(tests []acidTest) []string {
+		var names []string
+		for _, t := range tests {
+			names = append(names, t.name)
+		}
+		return names
+	}
+
+	tests, err := selectACIDTests(nil)
+	assert.NoError(t, err)
+	assert.Len(t, tests, 12)
+	tests, err = selectACIDTests([]string{"all"})
+	assert.NoError(t, err)
+	assert.Len(t, tests, 12)
+
+	// the tests run in the order of the spec
+	tests, err = selectACIDTests([]string{"durability", "Isolation-9", "atomicity"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"atomicity-1", "atomicity-2", "isolation-9", "durability"}, names(tests))
+
+	tests, err = selectACIDTests([]string{"isolation"})
+	assert.NoError(t, err)
+	assert.Len(t, tests, 9)
+
+	_, err = selectACIDTests([]string{"isolation-10"})
+	assert.Error(t, err)
+}
+
+func TestACIDPending(t *testing.T) {
+	release := make(chan struct{})
+	p := runPending(func() error {
+		<-release
+		return errors.New("aborted")
+	})
+	assert.True(t, p.blocked(10*time.Millisecond))
+	close(release)
+	assert.EqualError(t, p.wait(), "aborted")
+	assert.EqualError(t, p.wait(), "aborted")
+
+	p = runPending(func() error { return nil })
+	assert.False(t, p.blocked(time.Second))
+	assert.NoError(t, p.wait())
+}
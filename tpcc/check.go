@@ -15,8 +15,9 @@ func (w *Workloader) Check(ctx context.Context, threadID int) error {
 	return w.check(ctx, threadID, w.cfg.CheckAll)
 }
 
-// Check implements Workloader interface
-func (w *Workloader) check(ctx context.Context, threadID int, checkAll bool) error {
+// consistencyChecks returns the consistency conditions of 3.3.2 by name, the condition 3.3.2.11 is
+// only valid before any Delivery, so it is only included by checkAll.
+func (w *Workloader) consistencyChecks(checkAll bool) map[string]func(ctx context.Context, warehouse int) error {
 	// refer 3.3.2
 	checks := map[string]func(ctx context.Context, warehouse int) error{
 		"3.3.2.1":  w.checkCondition1,
@@ -31,23 +32,15 @@ func (w *Workloader) check(ctx context.Context, threadID int, checkAll bool) err
 		"3.3.2.10": w.checkCondition10,
 		"3.3.2.12": w.checkCondition12,
 	}
-
 	if checkAll {
-		checks = map[string]func(ctx context.Context, warehouse int) error{
-			"3.3.2.1":  w.checkCondition1,
-			"3.3.2.2":  w.checkCondition2,
-			"3.3.2.3":  w.checkCondition3,
-			"3.3.2.4":  w.checkCondition4,
-			"3.3.2.5":  w.checkCondition5,
-			"3.3.2.6":  w.checkCondition6,
-			"3.3.2.7":  w.checkCondition7,
-			"3.3.2.8":  w.checkCondition8,
-			"3.3.2.9":  w.checkCondition9,
-			"3.3.2.10": w.checkCondition10,
-			"3.3.2.11": w.checkCondition11,
-			"3.3.2.12": w.checkCondition12,
-		}
+		checks["3.3.2.11"] = w.checkCondition11
 	}
+	return checks
+}
+
+// Check implements Workloader interface
+func (w *Workloader) check(ctx context.Context, threadID int, checkAll bool) error {
+	checks := w.consistencyChecks(checkAll)
 
 	for i := threadID % w.cfg.Threads; i < w.cfg.Warehouses; i += w.cfg.Threads {
 		warehouse := i%w.cfg.Warehouses + 1
@@ -0,0 +1,289 @@
+package tpcc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+)
+
+const (
+	// deliveryCompletionLimit is the time in which 90% of the deferred deliveries must complete, refer 5.2.5.3
+	deliveryCompletionLimit = 80 * time.Second
+	deliveryQueueSize       = 65536
+	// deliveryDrainTimeout bounds the execution of the deliveries still queued after the last terminal,
+	// they would not complete within deliveryCompletionLimit anyway after it
+	deliveryDrainTimeout = deliveryCompletionLimit
+
+	resultTimeFormat = "2006-01-02 15:04:05.000"
+)
+
+type deliveryRequest struct {
+	deliveryData
+	queuedAt time.Time
+}
+
+// deliveryQueue executes the queued delivery transactions in deferred mode by a pool of workers,
+// and records the completion of every delivery in the result file, refer 2.7.2.
+// The workers are started with the first terminal and drain the queue after the last one, the
+// deliveries still queued when the drain is stopped are reported as not executed.
+type deliveryQueue struct {
+	w           *Workloader
+	workers     int
+	measurement *measurement.Measurement
+
+	mu          sync.Mutex
+	terminals   int
+	requests    chan deliveryRequest
+	stopWorkers context.CancelFunc
+	workersWg   sync.WaitGroup
+
+	resultMu   sync.Mutex
+	resultFile *os.File
+	result     *bufio.Writer
+
+	executed         int64
+	withinLimit      int64
+	skippedDistricts int64
+	notExecuted      int64
+}
+
+func newDeliveryQueue(w *Workloader) *deliveryQueue {
+	q := &deliveryQueue{
+		w:       w,
+		workers: w.cfg.DeliveryWorkers,
+		measurement: measurement.NewMeasurement(func(m *measurement.Measurement) {
+			m.MaxLatency = 2 * deliveryCompletionLimit
+			if w.cfg.MaxMeasureLatency > m.MaxLatency {
+				m.MaxLatency = w.cfg.MaxMeasureLatency
+			}
+		}),
+	}
+	if len(w.cfg.DeliveryResultFile) > 0 {
+		q.resultFile = util.CreateFile(w.cfg.DeliveryResultFile)
+		q.result = bufio.NewWriter(q.resultFile)
+	}
+	return q
+}
+
+// addTerminal registers a terminal queuing deliveries, the workers are started for the first one.
+func (q *deliveryQueue) addTerminal() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.terminals++
+	if q.terminals > 1 {
+		return
+	}
+	q.requests = make(chan deliveryRequest, deliveryQueueSize)
+	// the workers are not canceled with the terminals, so the deliveries queued until the end
+	// are executed
+	ctx, cancel := context.WithCancel(context.Background())
+	q.stopWorkers = cancel
+	q.workersWg.Add(q.workers)
+	for i := 0; i < q.workers; i++ {
+		go q.runWorker(ctx, q.requests)
+	}
+}
+
+// removeTerminal unregisters a terminal, the queued deliveries are executed before the last
+// terminal returns for at most deliveryDrainTimeout, or not at all if the run is interrupted.
+func (q *deliveryQueue) removeTerminal(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.terminals--
+	if q.terminals > 0 {
+		return
+	}
+	close(q.requests)
+	// the run context is already done at the end of --time, only an interruption stops the drain
+	if errors.Is(ctx.Err(), context.Canceled) {
+		q.stopWorkers()
+	}
+	timer := time.AfterFunc(deliveryDrainTimeout, q.stopWorkers)
+	q.workersWg.Wait()
+	timer.Stop()
+	q.stopWorkers()
+	if n := atomic.LoadInt64(&q.notExecuted); n > 0 {
+		fmt.Printf("%d queued deliveries were not executed before the end of the run\n", n)
+	}
+	q.flushResult()
+}
+
+func (q *deliveryQueue) enqueue(ctx context.Context, d deliveryData) error {
+	q.mu.Lock()
+	requests := q.requests
+	q.mu.Unlock()
+	select {
+	case requests <- deliveryRequest{deliveryData: d, queuedAt: time.Now()}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runWorker executes the queued deliveries on its own connection until the queue is closed, the
+// deliveries are not executed after the worker is stopped.
+func (q *deliveryQueue) runWorker(ctx context.Context, requests <-chan deliveryRequest) {
+	defer q.workersWg.Done()
+	// the connection is opened when the run starts, the statements are prepared without the stop
+	// of the worker which panics on their failures
+	s := &tpccState{TpcState: workload.NewTpcState(context.Background(), q.w.db)}
+	prepareCtx := context.WithValue(context.Background(), stateKey, s)
+	ctx = context.WithValue(ctx, stateKey, s)
+	s.deliveryStmts = prepareDeliveryStmts(q.w.cfg.Driver, prepareCtx, s.Conn)
+	defer func() {
+		closeStmts(s.deliveryStmts)
+		s.Conn.Close()
+	}()
+
+	for req := range requests {
+		if ctx.Err() != nil {
+			q.recordNotExecuted(req)
+			continue
+		}
+		if err := s.Conn.PingContext(ctx); err != nil {
+			if err := s.RefreshConn(ctx); err != nil {
+				if ctx.Err() != nil {
+					q.recordNotExecuted(req)
+				} else {
+					q.record(req, nil, err)
+				}
+				continue
+			}
+			closeStmts(s.deliveryStmts)
+			s.deliveryStmts = prepareDeliveryStmts(q.w.cfg.Driver, prepareCtx, s.Conn)
+		}
+		oIDs, err := q.w.deliver(ctx, req.deliveryData)
+		if err != nil && ctx.Err() != nil {
+			q.recordNotExecuted(req)
+			continue
+		}
+		q.record(req, oIDs, err)
+	}
+}
+
+// record measures the executed delivery from the time it is queued, and writes its result.
+func (q *deliveryQueue) record(req deliveryRequest, oIDs []int, err error) {
+	completedAt := time.Now()
+	if q.w.rtMeasurement.IsWarmUpFinished() {
+		elapsed := completedAt.Sub(req.queuedAt)
+		q.measurement.Measure("delivery_executed", elapsed, err)
+		if err == nil {
+			atomic.AddInt64(&q.executed, 1)
+			if elapsed <= deliveryCompletionLimit {
+				atomic.AddInt64(&q.withinLimit, 1)
+			}
+		}
+	}
+
+	var skipped []string
+	for i, oID := range oIDs {
+		if oID == 0 {
+			skipped = append(skipped, strconv.Itoa(i+1))
+		}
+	}
+	if err == nil && q.w.rtMeasurement.IsWarmUpFinished() {
+		atomic.AddInt64(&q.skippedDistricts, int64(len(skipped)))
+	}
+	if q.result == nil {
+		return
+	}
+	line := fmt.Sprintf("queued=%s completed=%s w_id=%d o_carrier_id=%d",
+		req.queuedAt.Format(resultTimeFormat), completedAt.Format(resultTimeFormat), req.wID, req.oCarrierID)
+	if err != nil {
+		line += fmt.Sprintf(" error=%q", err.Error())
+	} else {
+		ids := make([]string, len(oIDs))
+		for i, oID := range oIDs {
+			ids[i] = strconv.Itoa(oID)
+		}
+		line += fmt.Sprintf(" o_ids=%s skipped=%s", strings.Join(ids, ","), strings.Join(skipped, ","))
+	}
+	q.resultMu.Lock()
+	defer q.resultMu.Unlock()
+	q.result.WriteString(line + "\n")
+}
+
+// recordNotExecuted counts the delivery stopped before it is executed, and writes it to the result.
+func (q *deliveryQueue) recordNotExecuted(req deliveryRequest) {
+	atomic.AddInt64(&q.notExecuted, 1)
+	if q.result == nil {
+		return
+	}
+	line := fmt.Sprintf("queued=%s w_id=%d o_carrier_id=%d not_executed\n",
+		req.queuedAt.Format(resultTimeFormat), req.wID, req.oCarrierID)
+	q.resultMu.Lock()
+	defer q.resultMu.Unlock()
+	q.result.WriteString(line)
+}
+
+func (q *deliveryQueue) flushResult() {
+	if q.result == nil {
+		return
+	}
+	q.resultMu.Lock()
+	defer q.resultMu.Unlock()
+	if err := q.result.Flush(); err != nil {
+		fmt.Printf("failed to write delivery result file %s, err %v\n", q.resultFile.Name(), err)
+	}
+}
+
+// withinLimitPercent returns the percent of the deliveries completed within 80s, or false if no
+// delivery is executed or stopped. The deliveries not executed count as not completed within 80s.
+func (q *deliveryQueue) withinLimitPercent() (float64, bool) {
+	total := atomic.LoadInt64(&q.executed) + atomic.LoadInt64(&q.notExecuted)
+	if total == 0 {
+		return 0, false
+	}
+	return 100 * float64(atomic.LoadInt64(&q.withinLimit)) / float64(total), true
+}
+
+func (q *deliveryQueue) outputStats(ifSummaryReport bool, outputStyle string) {
+	q.measurement.Output(ifSummaryReport, outputStyle, outputRtMeasurement)
+	if !ifSummaryReport {
+		return
+	}
+	percent, ok := q.withinLimitPercent()
+	if !ok {
+		return
+	}
+	executed := atomic.LoadInt64(&q.executed)
+	notExecuted := atomic.LoadInt64(&q.notExecuted)
+	check := "PASS"
+	if percent < 90 {
+		check = "FAIL"
+	}
+	skipped := atomic.LoadInt64(&q.skippedDistricts)
+	skippedPercent := 0.0
+	if executed > 0 {
+		skippedPercent = 100 * float64(skipped) / float64(executed*districtPerWarehouse)
+	}
+	lines := [][]string{{
+		"[Deferred Delivery] ",
+		util.IntToString(executed),
+		util.IntToString(notExecuted),
+		util.FloatToTwoString(percent) + "%",
+		check,
+		util.IntToString(skipped),
+		util.FloatToTwoString(skippedPercent) + "%",
+	}}
+	headers := []string{"Prefix", "Executed", "Not Executed", "Within 80s", "Check(>=90%)", "Skipped Districts", "Skipped(%)"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%sexecuted: %s, not executed: %s, completed within 80s: %s, check(>=90%%): %s, skipped districts: %s (%s)\n", nil, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+}
@@ -0,0 +1,104 @@
+package tpcc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryQueue(t *testing.T) {
+	resultFile := filepath.Join(t.TempDir(), "delivery.log")
+	w := &Workloader{
+		cfg:           &Config{DeliveryWorkers: 1, DeliveryResultFile: resultFile},
+		rtMeasurement: measurement.NewMeasurement(),
+	}
+	q := newDeliveryQueue(w)
+
+	_, ok := q.withinLimitPercent()
+	assert.False(t, ok)
+
+	now := time.Now()
+	q.record(deliveryRequest{deliveryData: deliveryData{wID: 1, oCarrierID: 3}, queuedAt: now.Add(-time.Second)},
+		[]int{2101, 2101, 0, 2101, 2101, 2101, 2101, 2101, 2101, 2101}, nil)
+	q.record(deliveryRequest{deliveryData: deliveryData{wID: 2, oCarrierID: 4}, queuedAt: now.Add(-2 * deliveryCompletionLimit)},
+		[]int{2101, 2101, 2101, 2101, 2101, 2101, 2101, 2101, 2101, 2101}, nil)
+	q.record(deliveryRequest{deliveryData: deliveryData{wID: 3, oCarrierID: 5}, queuedAt: now}, nil, errors.New("conflict"))
+	q.flushResult()
+
+	percent, ok := q.withinLimitPercent()
+	assert.True(t, ok)
+	assert.Equal(t, 50.0, percent)
+	assert.Equal(t, int64(1), q.skippedDistricts)
+	assert.Equal(t, int64(2), q.measurement.OpSumMeasurement["delivery_executed"].TotalCount())
+	assert.Equal(t, int64(1), q.measurement.OpSumMeasurement["delivery_executed_ERR"].TotalCount())
+
+	data, err := os.ReadFile(resultFile)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "w_id=1 o_carrier_id=3 o_ids=2101,2101,0,2101,2101,2101,2101,2101,2101,2101 skipped=3")
+	assert.Contains(t, lines[1], "w_id=2 o_carrier_id=4 o_ids=2101,2101,2101,2101,2101,2101,2101,2101,2101,2101 skipped=")
+	assert.Contains(t, lines[2], `w_id=3 o_carrier_id=5 error="conflict"`)
+}
+
+func TestDeliveryQueueNotExecuted(t *testing.T) {
+	resultFile := filepath.Join(t.TempDir(), "delivery.log")
+	w := &Workloader{
+		cfg:           &Config{DeliveryWorkers: 1, DeliveryResultFile: resultFile},
+		rtMeasurement: measurement.NewMeasurement(),
+	}
+	q := newDeliveryQueue(w)
+
+	now := time.Now()
+	q.record(deliveryRequest{deliveryData: deliveryData{wID: 1, oCarrierID: 3}, queuedAt: now},
+		[]int{2101, 2101, 2101, 2101, 2101, 2101, 2101, 2101, 2101, 2101}, nil)
+	q.recordNotExecuted(deliveryRequest{deliveryData: deliveryData{wID: 2, oCarrierID: 4}, queuedAt: now})
+	q.flushResult()
+
+	// the deliveries not executed count as not completed within 80s
+	percent, ok := q.withinLimitPercent()
+	assert.True(t, ok)
+	assert.Equal(t, 50.0, percent)
+	assert.Equal(t, int64(1), q.notExecuted)
+	assert.Equal(t, int64(1), q.measurement.OpSumMeasurement["delivery_executed"].TotalCount())
+
+	data, err := os.ReadFile(resultFile)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "w_id=2 o_carrier_id=4 not_executed")
+}
+
+func TestDeliveryQueueTerminals(t *testing.T) {
+	w := &Workloader{cfg: &Config{}, rtMeasurement: measurement.NewMeasurement()}
+	q := newDeliveryQueue(w)
+
+	q.addTerminal()
+	q.addTerminal()
+	assert.NoError(t, q.enqueue(context.Background(), deliveryData{wID: 1}))
+	assert.Len(t, q.requests, 1)
+
+	// the queue is closed after the last terminal
+	q.removeTerminal(context.Background())
+	assert.NotPanics(t, func() { q.requests <- deliveryRequest{} })
+	q.removeTerminal(context.Background())
+	assert.Panics(t, func() { q.requests <- deliveryRequest{} })
+
+	// a canceled terminal doesn't wait for the full queue
+	q.addTerminal()
+	for i := 0; i < deliveryQueueSize; i++ {
+		q.requests <- deliveryRequest{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, q.enqueue(ctx, deliveryData{wID: 1}))
+	q.removeTerminal(context.Background())
+
+}
@@ -33,17 +33,49 @@ const (
 	deliveryUpdateCustomer = `UPDATE customer SET c_balance = c_balance + ?, c_delivery_cnt = c_delivery_cnt + 1 WHERE c_w_id = ? AND c_d_id = ? AND c_id = ?`
 )
 
-func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
+func prepareDeliveryStmts(driver string, ctx context.Context, conn *sql.Conn) map[string]*sql.Stmt {
+	return map[string]*sql.Stmt{
+		deliverySelectNewOrder:  prepareStmt(driver, ctx, conn, deliverySelectNewOrder),
+		deliveryDeleteNewOrder:  prepareStmt(driver, ctx, conn, deliveryDeleteNewOrder),
+		deliveryUpdateOrder:     prepareStmt(driver, ctx, conn, deliveryUpdateOrder),
+		deliverySelectOrders:    prepareStmt(driver, ctx, conn, deliverySelectOrders),
+		deliveryUpdateOrderLine: prepareStmt(driver, ctx, conn, deliveryUpdateOrderLine),
+		deliverySelectSumAmount: prepareStmt(driver, ctx, conn, deliverySelectSumAmount),
+		deliveryUpdateCustomer:  prepareStmt(driver, ctx, conn, deliveryUpdateCustomer),
+	}
+}
+
+// deliveryInput draws the input data of the delivery transaction, refer 2.7.1.
+func (w *Workloader) deliveryInput(ctx context.Context) func(ctx context.Context) error {
 	s := getTPCCState(ctx)
 
 	d := deliveryData{
-		wID:        randInt(s.R, 1, w.cfg.Warehouses),
+		wID:        w.homeWarehouse(s),
 		oCarrierID: randInt(s.R, 1, 10),
 	}
+	return func(ctx context.Context) error {
+		return w.runDelivery(ctx, d)
+	}
+}
+
+// runDelivery executes the delivery transaction, or queues it to be executed in deferred
+// mode if the delivery workers are enabled, refer 2.7.2.
+func (w *Workloader) runDelivery(ctx context.Context, d deliveryData) error {
+	if w.deliveryQueue != nil {
+		return w.deliveryQueue.enqueue(ctx, d)
+	}
+	_, err := w.deliver(ctx, d)
+	return err
+}
+
+// deliver delivers the oldest undelivered order of every district of the warehouse, and
+// returns the delivered order of every district, 0 if the district is skipped.
+func (w *Workloader) deliver(ctx context.Context, d deliveryData) ([]int, error) {
+	s := getTPCCState(ctx)
 
 	tx, err := w.beginTx(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 	type deliveryOrder struct {
@@ -56,7 +88,7 @@ func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
 		if err = s.deliveryStmts[deliverySelectNewOrder].QueryRowContext(ctx, d.wID, i+1).Scan(&orders[i].oID); err == sql.ErrNoRows {
 			continue
 		} else if err != nil {
-			return fmt.Errorf("exec %s failed %v", deliverySelectNewOrder, err)
+			return nil, fmt.Errorf("exec %s failed %w", deliverySelectNewOrder, err)
 		}
 	}
 
@@ -72,7 +104,7 @@ func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
 		d.wID, 9, orders[8].oID,
 		d.wID, 10, orders[9].oID,
 	); err != nil {
-		return fmt.Errorf("exec %s failed %v", deliveryDeleteNewOrder, err)
+		return nil, fmt.Errorf("exec %s failed %w", deliveryDeleteNewOrder, err)
 	}
 
 	if _, err = s.deliveryStmts[deliveryUpdateOrder].ExecContext(ctx, d.oCarrierID,
@@ -87,7 +119,7 @@ func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
 		d.wID, 9, orders[8].oID,
 		d.wID, 10, orders[9].oID,
 	); err != nil {
-		return fmt.Errorf("exec %s failed %v", deliveryUpdateOrder, err)
+		return nil, fmt.Errorf("exec %s failed %w", deliveryUpdateOrder, err)
 	}
 
 	if rows, err := s.deliveryStmts[deliverySelectOrders].QueryContext(ctx,
@@ -102,12 +134,12 @@ func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
 		d.wID, 9, orders[8].oID,
 		d.wID, 10, orders[9].oID,
 	); err != nil {
-		return fmt.Errorf("exec %s failed %v", deliverySelectOrders, err)
+		return nil, fmt.Errorf("exec %s failed %w", deliverySelectOrders, err)
 	} else {
 		for rows.Next() {
 			var dID, cID int
 			if err = rows.Scan(&dID, &cID); err != nil {
-				return fmt.Errorf("exec %s failed %v", deliverySelectOrders, err)
+				return nil, fmt.Errorf("exec %s failed %w", deliverySelectOrders, err)
 			}
 			orders[dID-1].cID = cID
 		}
@@ -125,7 +157,7 @@ func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
 		d.wID, 9, orders[8].oID,
 		d.wID, 10, orders[9].oID,
 	); err != nil {
-		return fmt.Errorf("exec %s failed %v", deliveryUpdateOrderLine, err)
+		return nil, fmt.Errorf("exec %s failed %w", deliveryUpdateOrderLine, err)
 	}
 
 	if rows, err := s.deliveryStmts[deliverySelectSumAmount].QueryContext(ctx,
@@ -140,13 +172,13 @@ func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
 		d.wID, 9, orders[8].oID,
 		d.wID, 10, orders[9].oID,
 	); err != nil {
-		return fmt.Errorf("exec %s failed %v", deliverySelectSumAmount, err)
+		return nil, fmt.Errorf("exec %s failed %w", deliverySelectSumAmount, err)
 	} else {
 		for rows.Next() {
 			var dID int
 			var amount float64
 			if err = rows.Scan(&dID, &amount); err != nil {
-				return fmt.Errorf("exec %s failed %v", deliverySelectOrders, err)
+				return nil, fmt.Errorf("exec %s failed %w", deliverySelectOrders, err)
 			}
 			orders[dID-1].amount = amount
 		}
@@ -158,8 +190,15 @@ func (w *Workloader) runDelivery(ctx context.Context, thread int) error {
 			continue
 		}
 		if _, err = s.deliveryStmts[deliveryUpdateCustomer].ExecContext(ctx, order.amount, d.wID, i+1, order.cID); err != nil {
-			return fmt.Errorf("exec %s failed %v", deliveryUpdateCustomer, err)
+			return nil, fmt.Errorf("exec %s failed %w", deliveryUpdateCustomer, err)
 		}
 	}
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	oIDs := make([]int, districtPerWarehouse)
+	for i := range orders {
+		oIDs[i] = orders[i].oID
+	}
+	return oIDs, nil
 }
@@ -2,6 +2,8 @@ package tpcc
 
 import "github.com/prometheus/client_golang/prometheus"
 
+// Deprecated: the tpc_tpcc_* gauges only snapshot the last report interval, they are kept for a
+// release alongside the tpc_op_* counters and histograms of pkg/measurement and will be removed.
 var (
 	elapsedVec = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
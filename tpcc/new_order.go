@@ -117,12 +117,13 @@ type newOrderData struct {
 	dTax     float64
 }
 
-func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
+// newOrderInput draws the input data of the new order transaction, refer 2.4.1.
+func (w *Workloader) newOrderInput(ctx context.Context) func(ctx context.Context) error {
 	s := getTPCCState(ctx)
 
 	// refer 2.4.1
 	d := newOrderData{
-		wID:    randInt(s.R, 1, w.cfg.Warehouses),
+		wID:    w.homeWarehouse(s),
 		dID:    randInt(s.R, 1, districtPerWarehouse),
 		cID:    randCustomerID(s.R),
 		oOlCnt: randInt(s.R, 5, 15),
@@ -133,7 +134,7 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 
 	items := make([]orderItem, d.oOlCnt)
 
-	itemsMap := make(map[int]*orderItem, d.oOlCnt)
+	chosen := make(map[int]struct{}, d.oOlCnt)
 
 	for i := 0; i < len(items); i++ {
 		item := &items[i]
@@ -144,10 +145,10 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 			for {
 				id := randItemID(s.R)
 				// Find a unique ID
-				if _, ok := itemsMap[id]; ok {
+				if _, ok := chosen[id]; ok {
 					continue
 				}
-				itemsMap[id] = item
+				chosen[id] = struct{}{}
 				item.olIID = id
 				break
 			}
@@ -164,6 +165,26 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 		item.olQuantity = randInt(s.R, 1, 10)
 	}
 
+	return func(ctx context.Context) error {
+		return w.runNewOrder(ctx, d, items, allLocal)
+	}
+}
+
+// runNewOrder executes the new order transaction of the input, the input items are not modified
+// so that the same input is executed again by the retries.
+func (w *Workloader) runNewOrder(ctx context.Context, d newOrderData, input []orderItem, allLocal int) error {
+	s := getTPCCState(ctx)
+
+	items := make([]orderItem, len(input))
+	copy(items, input)
+
+	itemsMap := make(map[int]*orderItem, len(items))
+	for i := range items {
+		if items[i].olIID > 0 {
+			itemsMap[items[i].olIID] = &items[i]
+		}
+	}
+
 	tx, err := w.beginTx(ctx)
 	if err != nil {
 		return err
@@ -174,17 +195,17 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 
 	// Process 1
 	if err := s.newOrderStmts[newOrderSelectCustomer].QueryRowContext(ctx, d.wID, d.dID, d.cID).Scan(&d.cDiscount, &d.cLast, &d.cCredit, &d.wTax); err != nil {
-		return fmt.Errorf("exec %s(wID=%d,dID=%d,cID=%d) failed %v", newOrderSelectCustomer, d.wID, d.dID, d.cID, err)
+		return fmt.Errorf("exec %s(wID=%d,dID=%d,cID=%d) failed %w", newOrderSelectCustomer, d.wID, d.dID, d.cID, err)
 	}
 
 	// Process 2
 	if err := s.newOrderStmts[newOrderSelectDistrict].QueryRowContext(ctx, d.dID, d.wID).Scan(&d.dNextOID, &d.dTax); err != nil {
-		return fmt.Errorf("exec %s failed %v", newOrderSelectDistrict, err)
+		return fmt.Errorf("exec %s failed %w", newOrderSelectDistrict, err)
 	}
 
 	// Process 3
 	if _, err := s.newOrderStmts[newOrderUpdateDistrict].ExecContext(ctx, d.dNextOID, d.dID, d.wID); err != nil {
-		return fmt.Errorf("exec %s failed %v", newOrderUpdateDistrict, err)
+		return fmt.Errorf("exec %s failed %w", newOrderUpdateDistrict, err)
 	}
 
 	oID := d.dNextOID
@@ -192,7 +213,7 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 	// Process 4
 	if _, err := s.newOrderStmts[newOrderInsertOrder].ExecContext(ctx, oID, d.dID, d.wID, d.cID,
 		time.Now().Format(timeFormat), d.oOlCnt, allLocal); err != nil {
-		return fmt.Errorf("exec %s failed %v", newOrderInsertOrder, err)
+		return fmt.Errorf("exec %s failed %w", newOrderInsertOrder, err)
 	}
 
 	// Process 5
@@ -200,7 +221,7 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 	// INSERT INTO new_order (no_o_id, no_d_id, no_w_id) VALUES (:o_id , :d _id , :w _id );
 	// query = `INSERT INTO new_order (no_o_id, no_d_id, no_w_id) VALUES (?, ?, ?)`
 	if _, err := s.newOrderStmts[newOrderInsertNewOrder].ExecContext(ctx, oID, d.dID, d.wID); err != nil {
-		return fmt.Errorf("exec %s failed %v", newOrderInsertNewOrder, err)
+		return fmt.Errorf("exec %s failed %w", newOrderInsertNewOrder, err)
 	}
 
 	// Process 6
@@ -211,13 +232,13 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 	}
 	rows, err := s.newOrderStmts[selectItemSQL].QueryContext(ctx, selectItemArgs...)
 	if err != nil {
-		return fmt.Errorf("exec %s failed %v", selectItemSQL, err)
+		return fmt.Errorf("exec %s failed %w", selectItemSQL, err)
 	}
 	for rows.Next() {
 		var tmpItem orderItem
 		err := rows.Scan(&tmpItem.iPrice, &tmpItem.iName, &tmpItem.iData, &tmpItem.olIID)
 		if err != nil {
-			return fmt.Errorf("exec %s failed %v", selectItemSQL, err)
+			return fmt.Errorf("exec %s failed %w", selectItemSQL, err)
 		}
 		item := itemsMap[tmpItem.olIID]
 		item.iPrice = tmpItem.iPrice
@@ -245,7 +266,7 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 	}
 	rows, err = s.newOrderStmts[selectStockSQL].QueryContext(ctx, selectStockArgs...)
 	if err != nil {
-		return fmt.Errorf("exec %s failed %v", selectStockSQL, err)
+		return fmt.Errorf("exec %s failed %w", selectStockSQL, err)
 	}
 	for rows.Next() {
 		var iID int
@@ -254,7 +275,7 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 		var dists [10]string
 		err = rows.Scan(&iID, &quantity, &data, &dists[0], &dists[1], &dists[2], &dists[3], &dists[4], &dists[5], &dists[6], &dists[7], &dists[8], &dists[9])
 		if err != nil {
-			return fmt.Errorf("exec %s failed %v", selectStockSQL, err)
+			return fmt.Errorf("exec %s failed %w", selectStockSQL, err)
 		}
 		item := itemsMap[iID]
 		quantity -= item.olQuantity
@@ -277,7 +298,7 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 			return nil
 		}
 		if _, err = s.newOrderStmts[newOrderUpdateStock].ExecContext(ctx, item.sQuantity, item.olQuantity, item.remoteWarehouse, item.olIID, d.wID); err != nil {
-			return fmt.Errorf("exec %s failed %v", newOrderUpdateStock, err)
+			return fmt.Errorf("exec %s failed %w", newOrderUpdateStock, err)
 		}
 	}
 
@@ -297,7 +318,7 @@ func (w *Workloader) runNewOrder(ctx context.Context, thread int) error {
 		insertOrderLineArgs[i*9+8] = item.sDist
 	}
 	if _, err = s.newOrderStmts[insertOrderLineSQL].ExecContext(ctx, insertOrderLineArgs...); err != nil {
-		return fmt.Errorf("exec %s failed %v", insertOrderLineSQL, err)
+		return fmt.Errorf("exec %s failed %w", insertOrderLineSQL, err)
 	}
 	return tx.Commit()
 }
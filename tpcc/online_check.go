@@ -0,0 +1,173 @@
+package tpcc
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+)
+
+// DefaultOnlineCheckConditions are the conditions checked in the background during run by default,
+// they are cheap enough to be checked on a warehouse while the transactions are running.
+var DefaultOnlineCheckConditions = []string{"3.3.2.1", "3.3.2.2", "3.3.2.3", "3.3.2.4"}
+
+// onlineChecker checks the sampled consistency conditions on a random warehouse every interval in
+// the background during run, all conditions of a round are checked in a snapshot on a low
+// priority connection. It is started with the first running thread and stopped after the last one.
+type onlineChecker struct {
+	w          *Workloader
+	interval   time.Duration
+	conditions []string
+	checks     map[string]func(ctx context.Context, warehouse int) error
+
+	mu      sync.Mutex
+	threads int
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	rounds   int64
+	failures int64
+}
+
+func newOnlineChecker(w *Workloader) (*onlineChecker, error) {
+	c := &onlineChecker{
+		w:          w,
+		interval:   w.cfg.CheckInterval,
+		conditions: w.cfg.CheckConditions,
+		// the condition 3.3.2.11 is not valid after any Delivery
+		checks: w.consistencyChecks(false),
+	}
+	if len(c.conditions) == 0 {
+		c.conditions = DefaultOnlineCheckConditions
+	}
+	for _, condition := range c.conditions {
+		if _, ok := c.checks[condition]; !ok {
+			return nil, fmt.Errorf("unknown consistency condition %s to check during run", condition)
+		}
+	}
+	return c, nil
+}
+
+// addThread registers a running thread, the checker is started for the first one.
+func (c *onlineChecker) addThread() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.threads++
+	if c.threads > 1 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.wg.Add(1)
+	go c.run(ctx)
+}
+
+// removeThread unregisters a running thread, the checker is stopped after the last one.
+func (c *onlineChecker) removeThread() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.threads--
+	if c.threads > 0 {
+		return
+	}
+	c.cancel()
+	c.wg.Wait()
+}
+
+func (c *onlineChecker) run(ctx context.Context) {
+	defer c.wg.Done()
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.checkRound(ctx, randInt(r, 1, c.w.cfg.Warehouses))
+	}
+}
+
+// snapshotBegin returns the statement beginning a transaction reading a consistent snapshot. The
+// transaction is not declared READ ONLY on MySQL protocol, since TiDB rejects it without AS OF
+// unless tidb_enable_noop_functions is on.
+func snapshotBegin(driver string) string {
+	if driver == "postgres" {
+		return "START TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"
+	}
+	return "START TRANSACTION WITH CONSISTENT SNAPSHOT"
+}
+
+// checkRound checks the conditions on the warehouse and reports every violation with the time it is
+// found. The errors caused by the stop of the checker are ignored.
+func (c *onlineChecker) checkRound(ctx context.Context, warehouse int) {
+	conn, err := c.w.db.Conn(ctx)
+	if err != nil {
+		c.report(ctx, warehouse, "", err)
+		return
+	}
+	defer conn.Close()
+	if c.w.cfg.Driver == "mysql" {
+		// lower the priority of the checks on TiDB, the error is ignored on the databases without it
+		if _, err := conn.ExecContext(ctx, "SET SESSION tidb_force_priority = 'LOW_PRIORITY'"); err == nil {
+			// the connection is returned to the pool shared with the threads
+			defer conn.ExecContext(context.Background(), "SET SESSION tidb_force_priority = 'NO_PRIORITY'")
+		}
+	}
+	if _, err := conn.ExecContext(ctx, snapshotBegin(c.w.cfg.Driver)); err != nil {
+		c.report(ctx, warehouse, "", err)
+		return
+	}
+	defer conn.ExecContext(context.Background(), "COMMIT")
+
+	s := &tpccState{TpcState: &workload.TpcState{DB: c.w.db, Conn: conn}}
+	checkCtx := context.WithValue(ctx, stateKey, s)
+	for _, condition := range c.conditions {
+		if err := c.checks[condition](checkCtx, warehouse); err != nil {
+			c.report(ctx, warehouse, condition, err)
+		}
+	}
+	if ctx.Err() == nil {
+		atomic.AddInt64(&c.rounds, 1)
+	}
+}
+
+func (c *onlineChecker) report(ctx context.Context, warehouse int, condition string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	atomic.AddInt64(&c.failures, 1)
+	now := time.Now().Format(resultTimeFormat)
+	if len(condition) == 0 {
+		fmt.Printf("[%s] failed to begin consistency check of warehouse %d, err %v\n", now, warehouse, err)
+		return
+	}
+	fmt.Printf("[%s] check warehouse %d at condition %s failed %v\n", now, warehouse, condition, err)
+}
+
+func (c *onlineChecker) failureCount() int64 {
+	return atomic.LoadInt64(&c.failures)
+}
+
+func (c *onlineChecker) outputStats(outputStyle string) {
+	lines := [][]string{{
+		"[Online Check] ",
+		util.IntToString(atomic.LoadInt64(&c.rounds)),
+		util.IntToString(c.failureCount()),
+	}}
+	headers := []string{"Prefix", "Rounds", "Failures"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%srounds: %s, failures: %s\n", nil, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+}
@@ -0,0 +1,53 @@
+package tpcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOnlineChecker(t *testing.T) {
+	w := &Workloader{cfg: &Config{Warehouses: 4, CheckInterval: time.Hour}}
+	c, err := newOnlineChecker(w)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultOnlineCheckConditions, c.conditions)
+
+	w.cfg.CheckConditions = []string{"3.3.2.12", "3.3.2.5"}
+	c, err = newOnlineChecker(w)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.3.2.12", "3.3.2.5"}, c.conditions)
+
+	// the condition 3.3.2.11 is broken by the deliveries during run
+	for _, condition := range []string{"3.3.2.11", "3.3.2.13", "1"} {
+		w.cfg.CheckConditions = []string{condition}
+		_, err = newOnlineChecker(w)
+		assert.Error(t, err, condition)
+	}
+}
+
+func TestOnlineCheckerThreads(t *testing.T) {
+	w := &Workloader{cfg: &Config{Warehouses: 4, CheckInterval: time.Hour}}
+	c, err := newOnlineChecker(w)
+	assert.NoError(t, err)
+
+	// the checker is started with the first thread and stopped after the last one
+	c.addThread()
+	c.addThread()
+	c.removeThread()
+	assert.Equal(t, 1, c.threads)
+	c.removeThread()
+	assert.Equal(t, 0, c.threads)
+	assert.Equal(t, int64(0), c.rounds)
+	assert.Equal(t, int64(0), c.failureCount())
+
+	// it is restarted by the next run
+	c.addThread()
+	c.removeThread()
+}
+
+func TestSnapshotBegin(t *testing.T) {
+	// TiDB rejects READ ONLY without AS OF by default
+	assert.Equal(t, "START TRANSACTION WITH CONSISTENT SNAPSHOT", snapshotBegin("mysql"))
+	assert.Equal(t, "START TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY", snapshotBegin("postgres"))
+}
@@ -29,10 +29,11 @@ type orderStatusData struct {
 	oCarrierID sql.NullInt64
 }
 
-func (w *Workloader) runOrderStatus(ctx context.Context, thread int) error {
+// orderStatusInput draws the input data of the order status transaction, refer 2.6.1.
+func (w *Workloader) orderStatusInput(ctx context.Context) func(ctx context.Context) error {
 	s := getTPCCState(ctx)
 	d := orderStatusData{
-		wID: randInt(s.R, 1, w.cfg.Warehouses),
+		wID: w.homeWarehouse(s),
 		dID: randInt(s.R, 1, districtPerWarehouse),
 	}
 
@@ -43,6 +44,15 @@ func (w *Workloader) runOrderStatus(ctx context.Context, thread int) error {
 		d.cID = randCustomerID(s.R)
 	}
 
+	return func(ctx context.Context) error {
+		return w.runOrderStatus(ctx, d)
+	}
+}
+
+// runOrderStatus executes the order status transaction of the input.
+func (w *Workloader) runOrderStatus(ctx context.Context, d orderStatusData) error {
+	s := getTPCCState(ctx)
+
 	tx, err := w.beginTx(ctx)
 	if err != nil {
 		return err
@@ -55,7 +65,7 @@ func (w *Workloader) runOrderStatus(ctx context.Context, thread int) error {
 		//	WHERE c_last=:c_last AND c_d_id=:d_id AND c_w_id=:w_id
 		var nameCnt int
 		if err := s.orderStatusStmts[orderStatusSelectCustomerCntByLast].QueryRowContext(ctx, d.wID, d.dID, d.cLast).Scan(&nameCnt); err != nil {
-			return fmt.Errorf("exec %s failed %v", orderStatusSelectCustomerCntByLast, err)
+			return fmt.Errorf("exec %s failed %w", orderStatusSelectCustomerCntByLast, err)
 		}
 		if nameCnt%2 == 1 {
 			nameCnt++
@@ -63,7 +73,7 @@ func (w *Workloader) runOrderStatus(ctx context.Context, thread int) error {
 
 		rows, err := s.orderStatusStmts[orderStatusSelectCustomerByLast].QueryContext(ctx, d.wID, d.dID, d.cLast)
 		if err != nil {
-			return fmt.Errorf("exec %s failed %v", orderStatusSelectCustomerByLast, err)
+			return fmt.Errorf("exec %s failed %w", orderStatusSelectCustomerByLast, err)
 		}
 		for i := 0; i < nameCnt/2 && rows.Next(); i++ {
 			if err := rows.Scan(&d.cBalance, &d.cFirst, &d.cMiddle, &d.cID); err != nil {
@@ -77,7 +87,7 @@ func (w *Workloader) runOrderStatus(ctx context.Context, thread int) error {
 		}
 	} else {
 		if err := s.orderStatusStmts[orderStatusSelectCustomerByID].QueryRowContext(ctx, d.wID, d.dID, d.cID).Scan(&d.cBalance, &d.cFirst, &d.cMiddle, &d.cLast); err != nil {
-			return fmt.Errorf("exec %s failed %v", orderStatusSelectCustomerByID, err)
+			return fmt.Errorf("exec %s failed %w", orderStatusSelectCustomerByID, err)
 		}
 	}
 
@@ -87,7 +97,7 @@ func (w *Workloader) runOrderStatus(ctx context.Context, thread int) error {
 
 	// refer 2.6.2.2 - select the latest order
 	if err := s.orderStatusStmts[orderStatusSelectLatestOrder].QueryRowContext(ctx, d.wID, d.dID, d.cID).Scan(&d.oID, &d.oCarrierID, &d.oEntryD); err != nil {
-		return fmt.Errorf("exec %s failed %v", orderStatusSelectLatestOrder, err)
+		return fmt.Errorf("exec %s failed %w", orderStatusSelectLatestOrder, err)
 	}
 
 	// SQL DECLARE c_line CURSOR FOR SELECT ol_i_id, ol_supply_w_id, ol_quantity,
@@ -97,7 +107,7 @@ func (w *Workloader) runOrderStatus(ctx context.Context, thread int) error {
 	// OPEN c_line;
 	rows, err := s.orderStatusStmts[orderStatusSelectOrderLine].QueryContext(ctx, d.wID, d.dID, d.oID)
 	if err != nil {
-		return fmt.Errorf("exec %s failed %v", orderStatusSelectOrderLine, err)
+		return fmt.Errorf("exec %s failed %w", orderStatusSelectOrderLine, err)
 	}
 	defer rows.Close()
 
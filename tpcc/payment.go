@@ -63,11 +63,12 @@ type paymentData struct {
 	cData      string
 }
 
-func (w *Workloader) runPayment(ctx context.Context, thread int) error {
+// paymentInput draws the input data of the payment transaction, refer 2.5.1.
+func (w *Workloader) paymentInput(ctx context.Context) func(ctx context.Context) error {
 	s := getTPCCState(ctx)
 
 	d := paymentData{
-		wID:     randInt(s.R, 1, w.cfg.Warehouses),
+		wID:     w.homeWarehouse(s),
 		dID:     randInt(s.R, 1, districtPerWarehouse),
 		hAmount: float64(randInt(s.R, 100, 500000)) / float64(100.0),
 	}
@@ -88,6 +89,15 @@ func (w *Workloader) runPayment(ctx context.Context, thread int) error {
 		d.cDID = randInt(s.R, 1, districtPerWarehouse)
 	}
 
+	return func(ctx context.Context) error {
+		return w.runPayment(ctx, d)
+	}
+}
+
+// runPayment executes the payment transaction of the input.
+func (w *Workloader) runPayment(ctx context.Context, d paymentData) error {
+	s := getTPCCState(ctx)
+
 	tx, err := w.beginTx(ctx)
 	if err != nil {
 		return err
@@ -96,37 +106,37 @@ func (w *Workloader) runPayment(ctx context.Context, thread int) error {
 
 	// Process 1
 	if _, err := s.paymentStmts[paymentUpdateDistrict].ExecContext(ctx, d.hAmount, d.wID, d.dID); err != nil {
-		return fmt.Errorf("exec %s failed %v", paymentUpdateDistrict, err)
+		return fmt.Errorf("exec %s failed %w", paymentUpdateDistrict, err)
 	}
 
 	// Process 2
 	if err := s.paymentStmts[paymentSelectDistrict].QueryRowContext(ctx, d.wID, d.dID).Scan(&d.dStreet1, &d.dStreet2,
 		&d.dCity, &d.dState, &d.dZip, &d.dName); err != nil {
-		return fmt.Errorf("exec %s failed %v", paymentSelectDistrict, err)
+		return fmt.Errorf("exec %s failed %w", paymentSelectDistrict, err)
 	}
 
 	// Process 3
 	if _, err := s.paymentStmts[paymentUpdateWarehouse].ExecContext(ctx, d.hAmount, d.wID); err != nil {
-		return fmt.Errorf("exec %s failed %v", paymentUpdateWarehouse, err)
+		return fmt.Errorf("exec %s failed %w", paymentUpdateWarehouse, err)
 	}
 
 	// Process 4
 	if err := s.paymentStmts[paymentSelectWarehouse].QueryRowContext(ctx, d.wID).Scan(&d.wStreet1, &d.wStreet2,
 		&d.wCity, &d.wState, &d.wZip, &d.wName); err != nil {
-		return fmt.Errorf("exec %s failed %v", paymentSelectDistrict, err)
+		return fmt.Errorf("exec %s failed %w", paymentSelectDistrict, err)
 	}
 
 	if d.cID == 0 {
 		// Process 5
 		rows, err := s.paymentStmts[paymentSelectCustomerListByLast].QueryContext(ctx, d.cWID, d.cDID, d.cLast)
 		if err != nil {
-			return fmt.Errorf("exec %s failed %v", paymentSelectCustomerListByLast, err)
+			return fmt.Errorf("exec %s failed %w", paymentSelectCustomerListByLast, err)
 		}
 		var ids []int
 		for rows.Next() {
 			var id int
 			if err = rows.Scan(&id); err != nil {
-				return fmt.Errorf("exec %s failed %v", paymentSelectCustomerListByLast, err)
+				return fmt.Errorf("exec %s failed %w", paymentSelectCustomerListByLast, err)
 			}
 			ids = append(ids, id)
 		}
@@ -140,13 +150,13 @@ func (w *Workloader) runPayment(ctx context.Context, thread int) error {
 	if err := s.paymentStmts[paymentSelectCustomerForUpdate].QueryRowContext(ctx, d.cWID, d.cDID, d.cID).Scan(&d.cFirst, &d.cMiddle, &d.cLast,
 		&d.cStreet1, &d.cStreet2, &d.cCity, &d.cState, &d.cZip, &d.cPhone, &d.cCredit, &d.cCreditLim,
 		&d.cDiscount, &d.cBalance, &d.cSince); err != nil {
-		return fmt.Errorf("exec %s failed %v", paymentSelectCustomerForUpdate, err)
+		return fmt.Errorf("exec %s failed %w", paymentSelectCustomerForUpdate, err)
 	}
 
 	if d.cCredit == "BC" {
 		// Process 7
 		if err := s.paymentStmts[paymentSelectCustomerData].QueryRowContext(ctx, d.cWID, d.cDID, d.cID).Scan(&d.cData); err != nil {
-			return fmt.Errorf("exec %s failed %v", paymentSelectCustomerData, err)
+			return fmt.Errorf("exec %s failed %w", paymentSelectCustomerData, err)
 		}
 
 		newData := fmt.Sprintf("| %4d %2d %4d %2d %4d $%7.2f %12s %24s", d.cID, d.cDID, d.cWID,
@@ -159,19 +169,19 @@ func (w *Workloader) runPayment(ctx context.Context, thread int) error {
 
 		// Process 8
 		if _, err := s.paymentStmts[paymentUpdateCustomerWithData].ExecContext(ctx, d.hAmount, d.hAmount, newData, d.cWID, d.cDID, d.cID); err != nil {
-			return fmt.Errorf("exec %s failed %v", paymentUpdateCustomerWithData, err)
+			return fmt.Errorf("exec %s failed %w", paymentUpdateCustomerWithData, err)
 		}
 	} else {
 		// Process 9
 		if _, err := s.paymentStmts[paymentUpdateCustomer].ExecContext(ctx, d.hAmount, d.hAmount, d.cWID, d.cDID, d.cID); err != nil {
-			return fmt.Errorf("exec %s failed %v", paymentUpdateCustomer, err)
+			return fmt.Errorf("exec %s failed %w", paymentUpdateCustomer, err)
 		}
 	}
 
 	// Process 10
 	hData := fmt.Sprintf("%10s    %10s", d.wName, d.dName)
 	if _, err := s.paymentStmts[paymentInsertHistory].ExecContext(ctx, d.cDID, d.cWID, d.cID, d.dID, d.wID, time.Now().Format(timeFormat), d.hAmount, hData); err != nil {
-		return fmt.Errorf("exec %s failed %v", paymentInsertHistory, err)
+		return fmt.Errorf("exec %s failed %w", paymentInsertHistory, err)
 	}
 
 	return tx.Commit()
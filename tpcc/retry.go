@@ -0,0 +1,110 @@
+package tpcc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	retryTotalVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tpc",
+			Subsystem: "tpcc",
+			Name:      "retry_total",
+			Help:      "The total count of transaction retries",
+		}, []string{"op"},
+	)
+	retrySuccessTotalVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tpc",
+			Subsystem: "tpcc",
+			Name:      "retry_success_total",
+			Help:      "The total count of transactions succeeded after retry",
+		}, []string{"op"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(retryTotalVec, retrySuccessTotalVec)
+}
+
+type retryStats struct {
+	retries   int64
+	succeeded int64
+}
+
+// parseRetryErrorClasses validates the error classes to retry.
+func parseRetryErrorClasses(classes []string) (map[util.ErrorClass]struct{}, error) {
+	res := make(map[util.ErrorClass]struct{}, len(classes))
+	for _, class := range classes {
+		found := false
+		for _, c := range util.ErrorClasses {
+			if string(c) == class {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown error class %q, should be one of %v", class, util.ErrorClasses)
+		}
+		res[util.ErrorClass(class)] = struct{}{}
+	}
+	return res, nil
+}
+
+// runTxn runs the transaction and retries it on the configured error classes, the input of
+// the transaction is drawn once and replayed by the retries, the backoff doubles after every retry.
+func (w *Workloader) runTxn(ctx context.Context, txn txn) error {
+	execute := txn.input(ctx)
+	backoff := w.cfg.TxnRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := execute(ctx)
+		if err == nil {
+			if attempt > 1 {
+				atomic.AddInt64(&w.retryStats[txn.name].succeeded, 1)
+				retrySuccessTotalVec.WithLabelValues(txn.name).Inc()
+			}
+			return nil
+		}
+		if attempt >= w.cfg.TxnRetryMaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		if _, ok := w.retryErrorClasses[util.ClassifyError(err)]; !ok {
+			return err
+		}
+
+		atomic.AddInt64(&w.retryStats[txn.name].retries, 1)
+		retryTotalVec.WithLabelValues(txn.name).Inc()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (w *Workloader) outputRetryStats() {
+	lines := [][]string{}
+	for _, txn := range w.txns {
+		stats := w.retryStats[txn.name]
+		lines = append(lines, []string{"[Retry] ", strings.ToUpper(txn.name),
+			util.IntToString(atomic.LoadInt64(&stats.retries)),
+			util.IntToString(atomic.LoadInt64(&stats.succeeded))})
+	}
+	headers := []string{"Prefix", "Operation", "Retries", "Succeeded after retry"}
+	switch w.cfg.OutputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", headers, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+}
@@ -0,0 +1,72 @@
+package tpcc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestRunTxnRetry(t *testing.T) {
+	classes, err := parseRetryErrorClasses([]string{"conflict"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &Workloader{
+		cfg:               &Config{TxnRetryMaxAttempts: 3},
+		retryErrorClasses: classes,
+		retryStats:        map[string]*retryStats{"payment": {}},
+	}
+
+	// every attempt replays the input drawn before the first one
+	attempts, inputs := 0, 0
+	var replayed []int
+	conflictOnce := txn{name: "payment", input: func(ctx context.Context) func(ctx context.Context) error {
+		inputs++
+		input := inputs
+		return func(ctx context.Context) error {
+			attempts++
+			replayed = append(replayed, input)
+			if attempts == 1 {
+				return &mysql.MySQLError{Number: 9007}
+			}
+			return nil
+		}
+	}}
+	if err := w.runTxn(context.Background(), conflictOnce); err != nil {
+		t.Fatalf("expect succeeded after retry, got %v", err)
+	}
+	if inputs != 1 || len(replayed) != 2 || replayed[0] != 1 || replayed[1] != 1 {
+		t.Fatalf("expect the input drawn once and replayed, got %d inputs and %v", inputs, replayed)
+	}
+	if stats := w.retryStats["payment"]; stats.retries != 1 || stats.succeeded != 1 {
+		t.Fatalf("expect 1 retry and 1 success, got %+v", stats)
+	}
+
+	attempts = 0
+	alwaysConflict := txn{name: "payment", input: func(ctx context.Context) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			attempts++
+			return &mysql.MySQLError{Number: 1213}
+		}
+	}}
+	if err := w.runTxn(context.Background(), alwaysConflict); err == nil || attempts != 3 {
+		t.Fatalf("expect failure after 3 attempts, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	notRetryable := txn{name: "payment", input: func(ctx context.Context) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			attempts++
+			return errors.New("item not found")
+		}
+	}}
+	if err := w.runTxn(context.Background(), notRetryable); err == nil || attempts != 1 {
+		t.Fatalf("expect no retry, got %v after %d attempts", err, attempts)
+	}
+
+	if _, err := parseRetryErrorClasses([]string{"deadlock"}); err == nil {
+		t.Fatalf("expect unknown error class")
+	}
+}
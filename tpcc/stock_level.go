@@ -8,7 +8,20 @@ const stockLevelCount = `SELECT /*+ TIDB_INLJ(order_line,stock) */ COUNT(DISTINC
 WHERE ol_w_id = ? AND ol_d_id = ? AND ol_o_id < ? AND ol_o_id >= ? - 20 AND s_w_id = ? AND s_i_id = ol_i_id AND s_quantity < ?`
 const stockLevelSelectDistrict = `SELECT d_next_o_id FROM district WHERE d_w_id = ? AND d_id = ?`
 
-func (w *Workloader) runStockLevel(ctx context.Context, thread int) error {
+// stockLevelInput draws the input data of the stock level transaction, refer 2.8.1.
+func (w *Workloader) stockLevelInput(ctx context.Context) func(ctx context.Context) error {
+	s := getTPCCState(ctx)
+
+	wID := w.homeWarehouse(s)
+	dID := w.homeDistrict(s)
+	threshold := randInt(s.R, 10, 20)
+	return func(ctx context.Context) error {
+		return w.runStockLevel(ctx, wID, dID, threshold)
+	}
+}
+
+// runStockLevel executes the stock level transaction of the input.
+func (w *Workloader) runStockLevel(ctx context.Context, wID, dID, threshold int) error {
 	s := getTPCCState(ctx)
 
 	tx, err := w.beginTx(ctx)
@@ -17,10 +30,6 @@ func (w *Workloader) runStockLevel(ctx context.Context, thread int) error {
 	}
 	defer tx.Rollback()
 
-	wID := randInt(s.R, 1, w.cfg.Warehouses)
-	dID := randInt(s.R, 1, 10)
-	threshold := randInt(s.R, 10, 20)
-
 	// SELECT d_next_o_id INTO :o_id FROM district WHERE d_w_id=:w_id AND d_id=:d_id;
 
 	var oID int
@@ -0,0 +1,41 @@
+package tpcc
+
+import "fmt"
+
+// terminalsPerWarehouse is the number of terminals of a warehouse, refer 4.2.2, every terminal
+// is bound to a district of its home warehouse.
+const terminalsPerWarehouse = districtPerWarehouse
+
+// checkTerminals checks that every district of every warehouse has exactly one terminal, so the
+// results are comparable with the audited ones, refer 4.2.2.
+func checkTerminals(cfg *Config) error {
+	if required := terminalsPerWarehouse * cfg.Warehouses; cfg.Threads != required {
+		return fmt.Errorf("%d threads mismatch the %d terminals of %d warehouses in terminal mode, run with --threads %d for %d terminals per warehouse",
+			cfg.Threads, required, cfg.Warehouses, required, terminalsPerWarehouse)
+	}
+	return nil
+}
+
+// terminal returns the home warehouse and district of the thread, the terminals are spread
+// over the warehouses round-robin so that every district has one terminal.
+func (w *Workloader) terminal(threadID int) (warehouse, district int) {
+	return threadID%w.cfg.Warehouses + 1, threadID/w.cfg.Warehouses%terminalsPerWarehouse + 1
+}
+
+// homeWarehouse returns the warehouse of the transaction, which is the home warehouse of the
+// terminal in terminal mode, refer 2.4.1.1, 2.5.1.1, 2.6.1.1, 2.7.1.1 and 2.8.1.1.
+func (w *Workloader) homeWarehouse(s *tpccState) int {
+	if w.cfg.Terminals {
+		return s.homeWID
+	}
+	return randInt(s.R, 1, w.cfg.Warehouses)
+}
+
+// homeDistrict returns the district of the stock level transaction, which is constant for
+// the terminal in terminal mode, refer 2.8.1.1.
+func (w *Workloader) homeDistrict(s *tpccState) int {
+	if w.cfg.Terminals {
+		return s.homeDID
+	}
+	return randInt(s.R, 1, districtPerWarehouse)
+}
@@ -0,0 +1,37 @@
+package tpcc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminal(t *testing.T) {
+	w := &Workloader{cfg: &Config{Warehouses: 3, Threads: 30, Terminals: true}}
+	assert.NoError(t, checkTerminals(w.cfg))
+
+	// every district of every warehouse has exactly one terminal
+	terminals := make(map[[2]int]int)
+	for threadID := 0; threadID < w.cfg.Threads; threadID++ {
+		warehouse, district := w.terminal(threadID)
+		assert.True(t, warehouse >= 1 && warehouse <= 3)
+		assert.True(t, district >= 1 && district <= districtPerWarehouse)
+		terminals[[2]int{warehouse, district}]++
+	}
+	assert.Len(t, terminals, 30)
+
+	// the terminals are spread over the warehouses round-robin
+	warehouse, district := w.terminal(1)
+	assert.Equal(t, 2, warehouse)
+	assert.Equal(t, 1, district)
+	warehouse, district = w.terminal(4)
+	assert.Equal(t, 2, warehouse)
+	assert.Equal(t, 2, district)
+
+	// every warehouse needs exactly 10 terminals
+	w.cfg.Threads = 31
+	assert.Error(t, checkTerminals(w.cfg))
+	w.cfg.Threads = 29
+	assert.EqualError(t, checkTerminals(w.cfg),
+		"29 threads mismatch the 30 terminals of 3 warehouses in terminal mode, run with --threads 30 for 10 terminals per warehouse")
+}
@@ -25,8 +25,10 @@ var tables = []string{tableItem, tableCustomer, tableDistrict, tableHistory,
 	tableNewOrder, tableOrderLine, tableOrders, tableStock, tableWareHouse}
 
 type txn struct {
-	name         string
-	action       func(ctx context.Context, threadID int) error
+	name string
+	// input draws the input data of the transaction and returns the function executing it, the
+	// function is called again with the same input by every retry
+	input        func(ctx context.Context) func(ctx context.Context) error
 	weight       int
 	keyingTime   float64
 	thinkingTime float64
@@ -46,6 +48,13 @@ type tpccState struct {
 
 	// for automatic connection refresh
 	lastConnRefresh time.Time
+
+	// the home warehouse and district of the terminal in terminal mode
+	homeWID int
+	homeDID int
+
+	// whether the thread is registered as running to the deferred delivery queue and the online checker
+	running bool
 }
 
 const (
@@ -74,8 +83,27 @@ type Config struct {
 	// whether to involve wait times(keying time&thinking time)
 	Wait bool
 
+	// whether to bind every thread to a home warehouse and district like a terminal of the spec
+	Terminals bool
+
+	// the number of workers executing the delivery transactions queued by the threads in
+	// deferred mode, 0 means the deliveries are executed by the threads
+	DeliveryWorkers int
+	// the file recording the result of every deferred delivery
+	DeliveryResultFile string
+
 	MaxMeasureLatency time.Duration
 
+	// the interval of the consistency checks running in the background during run, 0 means disabled
+	CheckInterval time.Duration
+	// the consistency conditions checked in the background during run, e.g. 3.3.2.1
+	CheckConditions []string
+
+	// for acid sub-command only
+	ACIDTests           []string
+	ACIDBlockWait       time.Duration
+	ACIDDurabilityPause time.Duration
+
 	// for prepare sub-command only
 	OutputType        string
 	OutputDir         string
@@ -91,6 +119,11 @@ type Config struct {
 
 	// automatic connection refresh interval to balance traffic across new replicas
 	ConnRefreshInterval time.Duration
+
+	// retry policy of transactions, the latency is measured across retries
+	TxnRetryMaxAttempts  int
+	TxnRetryBackoff      time.Duration
+	TxnRetryErrorClasses []string
 }
 
 // Workloader is TPCC workload
@@ -106,9 +139,17 @@ type Workloader struct {
 
 	txns []txn
 
+	retryErrorClasses map[util.ErrorClass]struct{}
+
+	// deliveryQueue is nil unless the deliveries are executed in deferred mode
+	deliveryQueue *deliveryQueue
+	// onlineChecker is nil unless the consistency is checked in the background during run
+	onlineChecker *onlineChecker
+
 	// stats
 	rtMeasurement       *measurement.Measurement
 	waitTimeMeasurement *measurement.Measurement
+	retryStats          map[string]*retryStats
 }
 
 // NewWorkloader creates the tpc-c workloader
@@ -148,16 +189,44 @@ func NewWorkloader(db *sql.DB, cfg *Config) (workload.Workloader, error) {
 		cfg:                 cfg,
 		initLoadTime:        time.Now().Format(timeFormat),
 		ddlManager:          newDDLManager(cfg.Parts, cfg.UseFK, cfg.Warehouses, cfg.PartitionType, cfg.UseClusteredIndex),
-		rtMeasurement:       measurement.NewMeasurement(resetMaxLat),
+		rtMeasurement:       measurement.NewMeasurement(resetMaxLat, measurement.WithMetrics("tpcc")),
 		waitTimeMeasurement: measurement.NewMeasurement(resetMaxLat),
 	}
 
 	w.txns = []txn{
-		{name: "new_order", action: w.runNewOrder, weight: cfg.Weight[0], keyingTime: 18, thinkingTime: 12},
-		{name: "payment", action: w.runPayment, weight: cfg.Weight[1], keyingTime: 3, thinkingTime: 12},
-		{name: "order_status", action: w.runOrderStatus, weight: cfg.Weight[2], keyingTime: 2, thinkingTime: 10},
-		{name: "delivery", action: w.runDelivery, weight: cfg.Weight[3], keyingTime: 2, thinkingTime: 5},
-		{name: "stock_level", action: w.runStockLevel, weight: cfg.Weight[4], keyingTime: 2, thinkingTime: 5},
+		{name: "new_order", input: w.newOrderInput, weight: cfg.Weight[0], keyingTime: 18, thinkingTime: 12},
+		{name: "payment", input: w.paymentInput, weight: cfg.Weight[1], keyingTime: 3, thinkingTime: 12},
+		{name: "order_status", input: w.orderStatusInput, weight: cfg.Weight[2], keyingTime: 2, thinkingTime: 10},
+		{name: "delivery", input: w.deliveryInput, weight: cfg.Weight[3], keyingTime: 2, thinkingTime: 5},
+		{name: "stock_level", input: w.stockLevelInput, weight: cfg.Weight[4], keyingTime: 2, thinkingTime: 5},
+	}
+
+	w.retryStats = make(map[string]*retryStats, len(w.txns))
+	for _, txn := range w.txns {
+		w.retryStats[txn.name] = &retryStats{}
+	}
+	var err error
+	if w.retryErrorClasses, err = parseRetryErrorClasses(cfg.TxnRetryErrorClasses); err != nil {
+		return nil, err
+	}
+	if cfg.Terminals {
+		if err := checkTerminals(cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.DeliveryWorkers < 0 {
+		return nil, fmt.Errorf("delivery workers %d must be >= 0", cfg.DeliveryWorkers)
+	}
+	if cfg.DeliveryWorkers > 0 {
+		w.deliveryQueue = newDeliveryQueue(w)
+	}
+	if cfg.CheckInterval < 0 {
+		return nil, fmt.Errorf("check interval %s must be >= 0", cfg.CheckInterval)
+	}
+	if cfg.CheckInterval > 0 {
+		if w.onlineChecker, err = newOnlineChecker(w); err != nil {
+			return nil, err
+		}
 	}
 
 	if w.db != nil {
@@ -188,6 +257,9 @@ func (w *Workloader) InitThread(ctx context.Context, threadID int) context.Conte
 	}
 
 	s.index = len(s.decks) - 1
+	if w.cfg.Terminals {
+		s.homeWID, s.homeDID = w.terminal(threadID)
+	}
 
 	ctx = context.WithValue(ctx, stateKey, s)
 
@@ -203,6 +275,14 @@ func (w *Workloader) CleanupThread(ctx context.Context, threadID int) {
 	closeStmts(s.stockLevelStmt)
 	closeStmts(s.orderStatusStmts)
 	// TODO: close stmts for delivery, order status, and stock level
+	if s.running {
+		if w.deliveryQueue != nil {
+			w.deliveryQueue.removeTerminal(ctx)
+		}
+		if w.onlineChecker != nil {
+			w.onlineChecker.removeThread()
+		}
+	}
 	if s.Conn != nil {
 		s.Conn.Close()
 	}
@@ -305,21 +385,23 @@ func (w *Workloader) Run(ctx context.Context, threadID int) (err error) {
 			orderStatusSelectLatestOrder:       prepareStmt(w.cfg.Driver, ctx, s.Conn, orderStatusSelectLatestOrder),
 			orderStatusSelectOrderLine:         prepareStmt(w.cfg.Driver, ctx, s.Conn, orderStatusSelectOrderLine),
 		}
-		s.deliveryStmts = map[string]*sql.Stmt{
-			deliverySelectNewOrder:  prepareStmt(w.cfg.Driver, ctx, s.Conn, deliverySelectNewOrder),
-			deliveryDeleteNewOrder:  prepareStmt(w.cfg.Driver, ctx, s.Conn, deliveryDeleteNewOrder),
-			deliveryUpdateOrder:     prepareStmt(w.cfg.Driver, ctx, s.Conn, deliveryUpdateOrder),
-			deliverySelectOrders:    prepareStmt(w.cfg.Driver, ctx, s.Conn, deliverySelectOrders),
-			deliveryUpdateOrderLine: prepareStmt(w.cfg.Driver, ctx, s.Conn, deliveryUpdateOrderLine),
-			deliverySelectSumAmount: prepareStmt(w.cfg.Driver, ctx, s.Conn, deliverySelectSumAmount),
-			deliveryUpdateCustomer:  prepareStmt(w.cfg.Driver, ctx, s.Conn, deliveryUpdateCustomer),
-		}
+		s.deliveryStmts = prepareDeliveryStmts(w.cfg.Driver, ctx, s.Conn)
 		s.stockLevelStmt = map[string]*sql.Stmt{
 			stockLevelSelectDistrict: prepareStmt(w.cfg.Driver, ctx, s.Conn, stockLevelSelectDistrict),
 			stockLevelCount:          prepareStmt(w.cfg.Driver, ctx, s.Conn, stockLevelCount),
 		}
 	}
 
+	if !s.running {
+		if w.deliveryQueue != nil {
+			w.deliveryQueue.addTerminal()
+		}
+		if w.onlineChecker != nil {
+			w.onlineChecker.addThread()
+		}
+		s.running = true
+	}
+
 	// refer 5.2.4.2
 	if s.index == len(s.decks) {
 		s.index = 0
@@ -341,8 +423,8 @@ func (w *Workloader) Run(ctx context.Context, threadID int) (err error) {
 		w.waitTimeMeasurement.Measure(fmt.Sprintf("keyingTime-%s", txn.name), time.Now().Sub(start), nil)
 	}
 
-	start := time.Now()
-	err = txn.action(ctx, threadID)
+	start := workload.StartTime(ctx)
+	err = w.runTxn(ctx, txn)
 
 	w.rtMeasurement.Measure(txn.name, time.Now().Sub(start), err)
 
@@ -408,6 +490,19 @@ func outputRtMeasurement(outputStyle string, prefix string, opMeasurement map[st
 	case util.OutputStyleJson:
 		util.RenderJson([]string{"Prefix", "Operation", "Takes(s)", "Count", "TPM", "Sum(ms)", "Avg(ms)", "50th(ms)", "90th(ms)", "95th(ms)", "99th(ms)", "99.9th(ms)", "Max(ms)"}, lines)
 	}
+
+	errLines := measurement.ErrorClassLines(prefix, opMeasurement)
+	if len(errLines) == 0 {
+		return
+	}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", measurement.ErrorClassHeaders, errLines)
+	case util.OutputStyleTable:
+		util.RenderTable(measurement.ErrorClassHeaders, errLines)
+	case util.OutputStyleJson:
+		util.RenderJson(measurement.ErrorClassHeaders, errLines)
+	}
 }
 
 func outputWaitTimesMeasurement(outputStyle string, prefix string, opMeasurement map[string]*measurement.Histogram) {
@@ -441,30 +536,22 @@ func (w *Workloader) OutputStats(ifSummaryReport bool) {
 	if w.cfg.Wait {
 		w.waitTimeMeasurement.Output(ifSummaryReport, w.cfg.OutputStyle, outputWaitTimesMeasurement)
 	}
+	if w.deliveryQueue != nil {
+		w.deliveryQueue.outputStats(ifSummaryReport, w.cfg.OutputStyle)
+	}
+	if ifSummaryReport && w.onlineChecker != nil {
+		w.onlineChecker.outputStats(w.cfg.OutputStyle)
+	}
+	if ifSummaryReport && w.cfg.TxnRetryMaxAttempts > 1 {
+		w.outputRetryStats()
+	}
 	if ifSummaryReport {
-		var (
-			newOrderHist *measurement.Histogram
-			totalOps     float64
-		)
-		for name, hist := range w.rtMeasurement.OpSumMeasurement {
-			if name == "new_order" {
-				newOrderHist = hist
-			}
-			if !strings.HasSuffix(name, "_ERR") {
-				totalOps += hist.GetInfo().Ops
-			}
-		}
-		if newOrderHist != nil && !newOrderHist.Empty() {
-			result := newOrderHist.GetInfo()
-			const specWarehouseFactor = 12.86
-			tpmC := result.Ops * 60
-			tpmTotal := totalOps * 60
-			efc := 100 * tpmC / (specWarehouseFactor * float64(w.cfg.Warehouses))
+		if results := w.Results(); results != nil {
 			lines := [][]string{
 				{
-					util.FloatToOneString(tpmC),
-					util.FloatToOneString(tpmTotal),
-					util.FloatToOneString(efc) + "%",
+					util.FloatToOneString(results["tpmC"]),
+					util.FloatToOneString(results["tpmTotal"]),
+					util.FloatToOneString(results["efficiency"]) + "%",
 				},
 			}
 			switch w.cfg.OutputStyle {
@@ -479,6 +566,50 @@ func (w *Workloader) OutputStats(ifSummaryReport bool) {
 	}
 }
 
+// Results implements workload.ResultProvider, it returns tpmC, tpmTotal and
+// efficiency, or nil if no new order transaction is measured. The percent of the
+// deferred deliveries completed within 80s is also returned in deferred mode, and the
+// failures of the consistency checks if they run in the background.
+func (w *Workloader) Results() map[string]float64 {
+	var (
+		newOrderHist *measurement.Histogram
+		totalOps     float64
+	)
+	for name, hist := range w.rtMeasurement.OpSumMeasurement {
+		if name == "new_order" {
+			newOrderHist = hist
+		}
+		if !strings.HasSuffix(name, "_ERR") {
+			totalOps += hist.GetInfo().Ops
+		}
+	}
+	if newOrderHist == nil || newOrderHist.Empty() {
+		return nil
+	}
+	result := newOrderHist.GetInfo()
+	const specWarehouseFactor = 12.86
+	tpmC := result.Ops * 60
+	results := map[string]float64{
+		"tpmC":       tpmC,
+		"tpmTotal":   totalOps * 60,
+		"efficiency": 100 * tpmC / (specWarehouseFactor * float64(w.cfg.Warehouses)),
+	}
+	if w.deliveryQueue != nil {
+		if percent, ok := w.deliveryQueue.withinLimitPercent(); ok {
+			results["deliveryWithin80s"] = percent
+		}
+	}
+	if w.onlineChecker != nil {
+		results["onlineCheckFailures"] = float64(w.onlineChecker.failureCount())
+	}
+	return results
+}
+
+// Measurement returns the response time measurement of the workload.
+func (w *Workloader) Measurement() *measurement.Measurement {
+	return w.rtMeasurement
+}
+
 // DBName returns the name of test db.
 func (w *Workloader) DBName() string {
 	return w.cfg.DBName
@@ -1,10 +1,15 @@
 package tpch
 
 import (
+	"bufio"
 	"database/sql"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 type precision int
@@ -51,14 +56,92 @@ var queryColPrecisions = map[string][]precision{
 	"q22": {num, cnt, sum},
 }
 
-func (w *Workloader) scanQueryResult(queryName string, rows *sql.Rows) error {
-	var got [][]string
+type answerKey struct {
+	scaleFactor float64
+	driver      string
+}
+
+// answerSets are the expected outputs of the queries with the default parameters of the driver.
+var answerSets = map[answerKey]map[string][][]string{
+	{1, "mysql"}: ans,
+}
+
+// CheckAnswerSet returns an error if there is no answer set to check the output for the scale factor and driver.
+func CheckAnswerSet(scaleFactor float64, driver string) error {
+	if _, ok := answerSets[answerKey{scaleFactor, driver}]; ok {
+		return nil
+	}
+	var available []string
+	for key := range answerSets {
+		available = append(available, fmt.Sprintf("--sf %g with %s", key.scaleFactor, key.driver))
+	}
+	sort.Strings(available)
+	return fmt.Errorf("no answer set for --sf %g with %s, available: %s", scaleFactor, driver, strings.Join(available, ", "))
+}
+
+// LoadAnswers loads the answers of the queries from the files named like q1.out in the directory
+// instead of the built-in answer set, so that the output can be checked at any scale factor.
+func (w *Workloader) LoadAnswers(dir string) error {
+	answers := make(map[string][][]string, len(queryColPrecisions))
+	for queryName := range queryColPrecisions {
+		rows, err := readAnswerFile(filepath.Join(dir, queryName+".out"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		answers[queryName] = rows
+	}
+	if len(answers) == 0 {
+		return fmt.Errorf("no answer files like q1.out in %s", dir)
+	}
+	w.answers = answers
+	return nil
+}
+
+// readAnswerFile reads an answer file in the format of the answers of dbgen, the first line is
+// the header and every other line is a row with the columns separated by '|'.
+func readAnswerFile(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		rows    [][]string
+		scanner = bufio.NewScanner(f)
+		header  = true
+	)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if header || len(strings.TrimSpace(line)) == 0 {
+			header = false
+			continue
+		}
+		// the answers of old dbgen versions end the lines with '|' and pad the columns with spaces
+		row := strings.Split(strings.TrimSuffix(line, "|"), "|")
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s failed %v", path, err)
+	}
+	return rows, nil
+}
 
+// ScanRows scans all the rows as strings, NULL is scanned as \N.
+func ScanRows(rows *sql.Rows) ([]string, [][]string, error) {
 	cols, err := rows.Columns()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
+	var got [][]string
 	for rows.Next() {
 		rawResult := make([][]byte, len(cols))
 		row := make([]string, len(cols))
@@ -69,7 +152,7 @@ func (w *Workloader) scanQueryResult(queryName string, rows *sql.Rows) error {
 		}
 
 		if err := rows.Scan(dest...); err != nil {
-			return fmt.Errorf("scan %s failed %v", queryName, err)
+			return nil, nil, err
 		}
 
 		for i, raw := range rawResult {
@@ -81,72 +164,96 @@ func (w *Workloader) scanQueryResult(queryName string, rows *sql.Rows) error {
 		}
 		got = append(got, row)
 	}
-	if w.cfg.ScaleFactor == 1 && w.cfg.EnableOutputCheck {
-		return checkOutput(queryColPrecisions[queryName], ans[queryName], got)
-	}
-	return nil
+	return cols, got, rows.Err()
 }
 
-func checkOutput(colPrecisions []precision, expect [][]string, got [][]string) (ret error) {
-	if len(expect) != len(got) {
-		return fmt.Errorf("expect %d rows, got %d rows", len(expect), len(got))
-	}
-	if len(expect) > 0 {
-		if len(expect[0]) != len(got[0]) {
-			return fmt.Errorf("expect %d columns, got %d columns", len(expect[0]), len(got[0]))
+func (w *Workloader) checkQueryResult(queryName string, cols []string, got [][]string) error {
+	if w.cfg.EnableOutputCheck && !w.cfg.EnableQGen && w.answers != nil {
+		expect, ok := w.answers[queryName]
+		if !ok {
+			return fmt.Errorf("no answer of %s", queryName)
 		}
+		return checkOutput(queryColPrecisions[queryName], cols, expect, got)
 	}
-	for i, row := range got {
-		for j, column := range row {
-			expectStr := expect[i][j]
-			ret = fmt.Errorf("expect %s at row %d column %d, got %s", expectStr, i, j, column)
-
-			// 2.1.3.5
-			switch colPrecisions[j] {
-			case cnt:
-				// For singleton column values and results from COUNT aggregates, the values must exactly match the query
-				// validation output data.
-				fallthrough
-			case num:
-				fallthrough
-			case str:
-				if expectStr != column {
-					return
-				}
-				continue
-			}
+	return nil
+}
 
-			expectFloat, err := strconv.ParseFloat(expectStr, 64)
-			if err != nil {
-				return
-			}
-			gotFloat, err := strconv.ParseFloat(column, 64)
-			if err != nil {
-				return
-			}
+// maxOutputDiffs is the max number of the differences reported by checkOutput.
+const maxOutputDiffs = 10
 
-			switch colPrecisions[j] {
-			case sum:
-				// For results from SUM aggregates, the resulting values must be within $100 of the query validation output
-				// data
-				if math.Abs(expectFloat-gotFloat) > 100.0 {
-					return
+// checkOutput returns an error listing the different rows and columns if the output doesn't match the answers.
+func checkOutput(colPrecisions []precision, cols []string, expect [][]string, got [][]string) error {
+	var diffs []string
+	for i := 0; i < len(expect) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, fmt.Sprintf("row %d: missing %s", i, strings.Join(expect[i], "|")))
+		case i >= len(expect):
+			diffs = append(diffs, fmt.Sprintf("row %d: unexpected %s", i, strings.Join(got[i], "|")))
+		case len(expect[i]) != len(got[i]):
+			diffs = append(diffs, fmt.Sprintf("row %d: expect %d columns, got %d columns", i, len(expect[i]), len(got[i])))
+		default:
+			for j, column := range got[i] {
+				colPrecision := str
+				if j < len(colPrecisions) {
+					colPrecision = colPrecisions[j]
 				}
-			case avg:
-				// For results from AVG aggregates, the resulting values r must be within 1% of the query validation output
-				// data when rounded to the nearest 1/100th. That is, 0.99*v<=round(r,2)<=1.01*v.
-				fallthrough
-			case rat:
-				// For ratios, results r must be within 1% of the query validation output data v when rounded to the nearest
-				// 1/100th. That is, 0.99*v<=round(r,2)<=1.01*v
-				if math.Abs(math.Round(gotFloat*1000)/1000-math.Round(expectFloat*1000)/1000) > 0.01 {
-					return
+				if !matchColumn(colPrecision, expect[i][j], column) {
+					diffs = append(diffs, fmt.Sprintf("row %d column %d (%s): expect %s, got %s", i, j, cols[j], expect[i][j], column))
 				}
-			default:
-				panic("unreachable")
 			}
 		}
 	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	total := len(diffs)
+	if total > maxOutputDiffs {
+		diffs = append(diffs[:maxOutputDiffs], fmt.Sprintf("... and %d more", total-maxOutputDiffs))
+	}
+	return fmt.Errorf("expect %d rows, got %d rows, %d differences:\n  %s", len(expect), len(got), total, strings.Join(diffs, "\n  "))
+}
 
-	return nil
+// matchColumn returns whether the column matches the expected one within the precision of 2.1.3.5.
+func matchColumn(colPrecision precision, expectStr string, column string) bool {
+	if expectStr == column {
+		return true
+	}
+	switch colPrecision {
+	case cnt:
+		// For singleton column values and results from COUNT aggregates, the values must exactly match the query
+		// validation output data.
+		fallthrough
+	case num:
+		fallthrough
+	case str:
+		// the columns of the answer files are trimmed
+		return expectStr == strings.TrimSpace(column)
+	}
+
+	expectFloat, err := strconv.ParseFloat(expectStr, 64)
+	if err != nil {
+		return false
+	}
+	gotFloat, err := strconv.ParseFloat(column, 64)
+	if err != nil {
+		return false
+	}
+
+	switch colPrecision {
+	case sum:
+		// For results from SUM aggregates, the resulting values must be within $100 of the query validation output
+		// data
+		return math.Abs(expectFloat-gotFloat) <= 100.0
+	case avg:
+		// For results from AVG aggregates, the resulting values r must be within 1% of the query validation output
+		// data when rounded to the nearest 1/100th. That is, 0.99*v<=round(r,2)<=1.01*v.
+		fallthrough
+	case rat:
+		// For ratios, results r must be within 1% of the query validation output data v when rounded to the nearest
+		// 1/100th. That is, 0.99*v<=round(r,2)<=1.01*v
+		return math.Abs(math.Round(gotFloat*1000)/1000-math.Round(expectFloat*1000)/1000) <= 0.01
+	default:
+		panic("unreachable")
+	}
 }
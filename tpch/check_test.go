@@ -0,0 +1,47 @@
+package tpch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAnswerSet(t *testing.T) {
+	assert.NoError(t, CheckAnswerSet(1, "mysql"))
+	assert.EqualError(t, CheckAnswerSet(0.01, "mysql"), "no answer set for --sf 0.01 with mysql, available: --sf 1 with mysql")
+}
+
+func TestReadAnswerFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "q13.out")
+	content := "c_count|custdist\n0|50005\n9  |6641|\n\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	rows, err := readAnswerFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"0", "50005"}, {"9", "6641"}}, rows)
+}
+
+func TestLoadAnswers(t *testing.T) {
+	dir := t.TempDir()
+	w := &Workloader{cfg: &Config{}}
+	assert.Error(t, w.LoadAnswers(dir))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "q6.out"), []byte("revenue\n123141078.23\n"), 0644))
+	assert.NoError(t, w.LoadAnswers(dir))
+	assert.Equal(t, map[string][][]string{"q6": {{"123141078.23"}}}, w.answers)
+}
+
+func TestCheckOutput(t *testing.T) {
+	precisions := []precision{str, sum, cnt}
+	cols := []string{"name", "revenue", "count"}
+	expect := [][]string{{"a", "100.00", "1"}, {"b", "200.00", "2"}}
+
+	assert.NoError(t, checkOutput(precisions, cols, expect, [][]string{{"a", "150.00", "1"}, {"b", "200.0000", "2"}}))
+	assert.EqualError(t, checkOutput(precisions, cols, expect, [][]string{{"a", "250.00", "1"}, {"b", "200.00", "3"}}),
+		"expect 2 rows, got 2 rows, 2 differences:\n"+
+			"  row 0 column 1 (revenue): expect 100.00, got 250.00\n"+
+			"  row 1 column 2 (count): expect 2, got 3")
+	assert.EqualError(t, checkOutput(precisions, cols, expect, [][]string{{"a", "100.00", "1"}}),
+		"expect 2 rows, got 1 rows, 1 differences:\n  row 1: missing b|200.00|2")
+}
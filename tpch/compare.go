@@ -0,0 +1,194 @@
+package tpch
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/go-tpc/pkg/util"
+)
+
+type compareStats struct {
+	compared   int64
+	mismatched int64
+	failed     int64
+}
+
+// Comparer runs the queries against a reference database and compares the results with the ones of
+// the tested database, the reference queries are not measured.
+type Comparer struct {
+	db *sql.DB
+
+	mu    sync.Mutex
+	stats map[string]*compareStats
+}
+
+// NewComparer returns a comparer of the reference database.
+func NewComparer(db *sql.DB) *Comparer {
+	return &Comparer{
+		db:    db,
+		stats: make(map[string]*compareStats),
+	}
+}
+
+// Exec executes the statement on the reference database, e.g. to create the views used by the queries.
+func (c *Comparer) Exec(sql string) error {
+	_, err := c.db.Exec(sql)
+	return err
+}
+
+// Close closes the reference database.
+func (c *Comparer) Close() error {
+	return c.db.Close()
+}
+
+// Compare runs the query against the reference database and reports the differences from the result
+// regardless of the order of the rows, the numeric columns are compared within the precision of the ratios.
+func (c *Comparer) Compare(ctx context.Context, queryName, query string, cols []string, got [][]string) {
+	c.compare(ctx, queryName, query, nil, cols, got)
+}
+
+func (c *Comparer) compare(ctx context.Context, queryName, query string, colPrecisions []precision, cols []string, got [][]string) {
+	expect, err := c.query(ctx, query)
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats, ok := c.stats[queryName]
+	if !ok {
+		stats = &compareStats{}
+		c.stats[queryName] = stats
+	}
+	if err != nil {
+		stats.failed++
+		util.StdErrLogger.Printf("execute %s on the compare target failed %v", queryName, err)
+		return
+	}
+
+	stats.compared++
+	if colPrecisions == nil {
+		colPrecisions = inferPrecisions(expect)
+	}
+	// the rows are compared as multisets, since the order of the rows is not guaranteed without
+	// ORDER BY or with ties in the ORDER BY columns, so the reported row numbers are of the sorted rows
+	if err := checkOutput(colPrecisions, cols, sortRows(expect), sortRows(got)); err != nil {
+		stats.mismatched++
+		util.StdErrLogger.Printf("result of %s mismatches the compare target, %v", queryName, err)
+	}
+}
+
+func (c *Comparer) query(ctx context.Context, query string) ([][]string, error) {
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	_, expect, err := ScanRows(rows)
+	return expect, err
+}
+
+// sortRows returns the rows sorted by the columns from left to right, the numbers are sorted by
+// value so that the decimals with different scales are sorted alike, and before the other values.
+func sortRows(rows [][]string) [][]string {
+	sorted := make([][]string, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, k int) bool {
+		a, b := sorted[i], sorted[k]
+		for j := 0; j < len(a) && j < len(b); j++ {
+			if c := compareColumn(a[j], b[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return len(a) < len(b)
+	})
+	return sorted
+}
+
+func compareColumn(a, b string) int {
+	fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case errA == nil && errB == nil:
+		if fa < fb {
+			return -1
+		} else if fa > fb {
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// inferPrecisions compares the columns of decimals as ratios and the other columns exactly.
+func inferPrecisions(rows [][]string) []precision {
+	if len(rows) == 0 {
+		return nil
+	}
+	precisions := make([]precision, len(rows[0]))
+	for j := range precisions {
+		decimal := false
+		for _, row := range rows {
+			if row[j] == "\\N" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(row[j], 64); err != nil {
+				decimal = false
+				break
+			}
+			if strings.Contains(row[j], ".") {
+				decimal = true
+			}
+		}
+		if decimal {
+			precisions[j] = rat
+		}
+	}
+	return precisions
+}
+
+// Mismatched returns the number of the compared queries whose results mismatched or failed on the reference database.
+func (c *Comparer) Mismatched() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	for _, stats := range c.stats {
+		n += stats.mismatched + stats.failed
+	}
+	return n
+}
+
+// OutputStats outputs the number of compared and mismatched results of every query.
+func (c *Comparer) OutputStats(outputStyle string) {
+	c.mu.Lock()
+	queryNames := make([]string, 0, len(c.stats))
+	for queryName := range c.stats {
+		queryNames = append(queryNames, queryName)
+	}
+	sort.Strings(queryNames)
+	lines := [][]string{}
+	for _, queryName := range queryNames {
+		stats := c.stats[queryName]
+		lines = append(lines, []string{"[Compare] ", strings.ToUpper(queryName),
+			util.IntToString(stats.compared), util.IntToString(stats.mismatched), util.IntToString(stats.failed)})
+	}
+	c.mu.Unlock()
+
+	headers := []string{"Prefix", "Operation", "Compared", "Mismatched", "Failed"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", headers, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+}
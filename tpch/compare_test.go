@@ -0,0 +1,51 @@
+package tpch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferPrecisions(t *testing.T) {
+	rows := [][]string{
+		{"AIR", "12", "1234.5600", "\\N", "1.5"},
+		{"MAIL", "13", "\\N", "\\N", "abc"},
+	}
+	assert.Equal(t, []precision{str, str, rat, str, str}, inferPrecisions(rows))
+	assert.Nil(t, inferPrecisions(nil))
+
+	// the tested database may output decimals with different scales
+	assert.NoError(t, checkOutput(inferPrecisions(rows), []string{"a", "b", "c", "d", "e"}, rows,
+		[][]string{{"AIR", "12", "1234.56", "\\N", "1.5"}, {"MAIL", "13", "\\N", "\\N", "abc"}}))
+}
+
+func TestSortRows(t *testing.T) {
+	expect := [][]string{
+		{"MAIL", "13", "1234.5600"},
+		{"AIR", "9", "\\N"},
+		{"AIR", "12", "99.5000"},
+		{"AIR", "12", "100.0000"},
+	}
+	// the same rows in a different order and with different scales of the decimals
+	got := [][]string{
+		{"AIR", "12", "100"},
+		{"AIR", "9", "\\N"},
+		{"AIR", "12", "99.5"},
+		{"MAIL", "13", "1234.56"},
+	}
+	cols := []string{"a", "b", "c"}
+	assert.Error(t, checkOutput(inferPrecisions(expect), cols, expect, got))
+	assert.NoError(t, checkOutput(inferPrecisions(expect), cols, sortRows(expect), sortRows(got)))
+	assert.Equal(t, [][]string{
+		{"AIR", "9", "\\N"},
+		{"AIR", "12", "99.5000"},
+		{"AIR", "12", "100.0000"},
+		{"MAIL", "13", "1234.5600"},
+	}, sortRows(expect))
+	// the rows themselves are not reordered
+	assert.Equal(t, "MAIL", expect[0][0])
+
+	// a different row is still reported as a multiset
+	got[0] = []string{"AIR", "12", "101"}
+	assert.Error(t, checkOutput(inferPrecisions(expect), cols, sortRows(expect), sortRows(got)))
+}
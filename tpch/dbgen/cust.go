@@ -60,26 +60,26 @@ func NewCustLoader(w io.Writer) custLoader {
 	return custLoader{sink.NewCSVSinkWithDelimiter(w, '|')}
 }
 
-func sdCust(child Table, skipCount dssHuge) {
-	advanceStream(cAddrSd, skipCount*9, false)
-	advanceStream(cCmntSd, skipCount*2, false)
-	advanceStream(lNtrgSd, skipCount, false)
-	advanceStream(cPhneSd, skipCount*3, false)
-	advanceStream(cAbalSd, skipCount, false)
-	advanceStream(cMsegSd, skipCount, false)
+func (g *generator) sdCust(child Table, skipCount dssHuge) {
+	g.advanceStream(cAddrSd, skipCount*9, false)
+	g.advanceStream(cCmntSd, skipCount*2, false)
+	g.advanceStream(lNtrgSd, skipCount, false)
+	g.advanceStream(cPhneSd, skipCount*3, false)
+	g.advanceStream(cAbalSd, skipCount, false)
+	g.advanceStream(cMsegSd, skipCount, false)
 }
 
-func makeCust(idx dssHuge) *Cust {
+func (g *generator) makeCust(idx dssHuge) *Cust {
 	cust := &Cust{}
 	cust.CustKey = idx
 	cust.Name = fmt.Sprintf("Customer#%09d", idx)
-	cust.Address = vStr(cAddrLen, cAddrSd)
-	i := random(0, dssHuge(nations.count-1), lNtrgSd)
+	cust.Address = g.vStr(cAddrLen, cAddrSd)
+	i := g.random(0, dssHuge(nations.count-1), lNtrgSd)
 	cust.NationCode = i
-	cust.Phone = genPhone(i, cPhneSd)
-	cust.Acctbal = random(cAbalMin, cAbalMax, cAbalSd)
-	pickStr(&cMsegSet, cMsegSd, &cust.MktSegment)
-	cust.Comment = makeText(cCmntLen, cCmntSd)
+	cust.Phone = g.genPhone(i, cPhneSd)
+	cust.Acctbal = g.random(cAbalMin, cAbalMax, cAbalSd)
+	g.pickStr(&cMsegSet, cMsegSd, &cust.MktSegment)
+	cust.Comment = g.makeText(cCmntLen, cCmntSd)
 
 	return cust
 }
@@ -37,7 +37,6 @@ type distribution struct {
 	count   int
 	max     int32
 	members []setMember
-	permute []long
 }
 
 func readDist(name string, d *distribution) {
@@ -49,21 +48,25 @@ func readDist(name string, d *distribution) {
 	}
 }
 
-func permute(permute []long, count int, stream long) {
+func (g *generator) permute(permute []long, count int, stream long) {
 	for i := 0; i < count; i++ {
-		source := random(dssHuge(i), dssHuge(count-1), stream)
+		source := g.random(dssHuge(i), dssHuge(count-1), stream)
 		permute[source], permute[i] = permute[i], permute[source]
 	}
 }
 
-func permuteDist(dist *distribution, stream long) {
-	if len(dist.permute) == 0 {
-		dist.permute = make([]long, dist.count)
+// permuteDist returns a permutation of the members of dist in the buffer of the generator,
+// the distributions are shared by all the generators.
+func (g *generator) permuteDist(dist *distribution, stream long) []long {
+	if len(g.perm) < dist.count {
+		g.perm = make([]long, dist.count)
 	}
-	for i := 0; i < dist.count; i++ {
-		dist.permute[i] = long(i)
+	perm := g.perm[:dist.count]
+	for i := range perm {
+		perm[i] = long(i)
 	}
-	permute(dist.permute, dist.count, stream)
+	g.permute(perm, dist.count, stream)
+	return perm
 }
 
 func initDists() {
@@ -16,37 +16,39 @@ type tDef struct {
 	name    string
 	comment string
 	base    dssHuge
-	loader  Loader
-	genSeed func(Table, dssHuge)
+	genSeed func(*generator, Table, dssHuge)
 	child   Table
 	vTotal  dssHuge
 }
 
 var tDefs []tDef
 
-func genTbl(tnum Table, start, count dssHuge) error {
-	loader := tDefs[tnum].loader
+// genTbl generates the rows [start, start+count) of the table with a new generator,
+// whose streams jump to the start row.
+func genTbl(loader Loader, tnum Table, start, count dssHuge) error {
 	defer loader.Flush()
 
+	g := newGenerator()
+	g.skipRows(tnum, start-1)
 	for i := start; i < start+count; i++ {
-		rowStart(tnum)
+		g.rowStart(tnum)
 		switch tnum {
 		case TLine:
 			fallthrough
 		case TOrder:
 			fallthrough
 		case TOrderLine:
-			order := makeOrder(i)
+			order := g.makeOrder(i, 0)
 			if err := loader.Load(order); err != nil {
 				return err
 			}
 		case TSupp:
-			supp := makeSupp(i)
+			supp := g.makeSupp(i)
 			if err := loader.Load(supp); err != nil {
 				return err
 			}
 		case TCust:
-			cust := makeCust(i)
+			cust := g.makeCust(i)
 			if err := loader.Load(cust); err != nil {
 				return err
 			}
@@ -55,67 +57,126 @@ func genTbl(tnum Table, start, count dssHuge) error {
 		case TPart:
 			fallthrough
 		case TPartPsupp:
-			part := makePart(i)
+			part := g.makePart(i)
 			if err := loader.Load(part); err != nil {
 				return err
 			}
 		case TNation:
-			nation := makeNation(i)
+			nation := g.makeNation(i)
 			if err := loader.Load(nation); err != nil {
 				return err
 			}
 		case TRegion:
-			region := makeRegion(i)
+			region := g.makeRegion(i)
 			if err := loader.Load(region); err != nil {
 				return err
 			}
 		}
-		rowStop(tnum)
+		g.rowStop(tnum)
 	}
 	return nil
 }
 
+// skipRows advances the streams of the table and its child over n rows, the orders and the parts
+// are always generated with their lineitems and partsupps, so their streams are advanced together.
+func (g *generator) skipRows(tnum Table, n dssHuge) {
+	if n == 0 {
+		return
+	}
+	switch tnum {
+	case TOrder, TLine:
+		tnum = TOrderLine
+	case TPart, TPsupp:
+		tnum = TPartPsupp
+	}
+	tDefs[tnum].genSeed(g, TNone, n)
+	if child := tDefs[tnum].child; child != TNone {
+		tDefs[child].genSeed(g, TNone, n)
+	}
+}
+
 func initTDefs() {
 	tDefs = []tDef{
-		{"part.tbl", "part table", 200000, nil, sdPart, TPsupp, 0},
-		{"partsupp.tbl", "partsupplier table", 200000, nil, sdPsupp, TNone, 0},
-		{"supplier.tbl", "suppliers table", 10000, nil, sdSupp, TNone, 0},
-		{"customer.tbl", "customers table", 150000, nil, sdCust, TNone, 0},
-		{"orders.tbl", "order table", 150000 * ordersPerCust, nil, sdOrder, TLine, 0},
-		{"lineitem.tbl", "lineitem table", 150000 * ordersPerCust, nil, sdLineItem, TNone, 0},
-		{"orders.tbl", "orders/lineitem tables", 150000 * ordersPerCust, newOrderLineLoader(), sdOrder, TLine, 0},
-		{"part.tbl", "part/partsupplier tables", 200000, newPartPsuppLoader(), sdPart, TPsupp, 0},
-		{"nation.tbl", "nation table", dssHuge(nations.count), nil, sdNull, TNone, 0},
-		{"region.tbl", "region table", dssHuge(regions.count), nil, sdNull, TNone, 0},
+		{"part.tbl", "part table", 200000, (*generator).sdPart, TPsupp, 0},
+		{"partsupp.tbl", "partsupplier table", 200000, (*generator).sdPsupp, TNone, 0},
+		{"supplier.tbl", "suppliers table", 10000, (*generator).sdSupp, TNone, 0},
+		{"customer.tbl", "customers table", 150000, (*generator).sdCust, TNone, 0},
+		{"orders.tbl", "order table", 150000 * ordersPerCust, (*generator).sdOrder, TLine, 0},
+		{"lineitem.tbl", "lineitem table", 150000 * ordersPerCust, (*generator).sdLineItem, TNone, 0},
+		{"orders.tbl", "orders/lineitem tables", 150000 * ordersPerCust, (*generator).sdOrder, TLine, 0},
+		{"part.tbl", "part/partsupplier tables", 200000, (*generator).sdPart, TPsupp, 0},
+		{"nation.tbl", "nation table", dssHuge(nations.count), (*generator).sdNull, TNone, 0},
+		{"region.tbl", "region table", dssHuge(regions.count), (*generator).sdNull, TNone, 0},
 	}
 }
 
-func InitDbGen(sc int64) {
+// InitDbGen initializes the generator for the scale factor. Like dbgen, a scale factor less than 1
+// scales down the base row counts of the tables in units of 0.001, and a larger one is truncated to an integer.
+func InitDbGen(sc float64) {
 	scale = dssHuge(sc)
-	initSeeds()
+	if sc < 1 {
+		scale = 1
+	}
 	initDists()
 	initTextPool()
 
 	initTDefs()
+	if sc < 1 {
+		scaleDownTDefs(sc)
+	}
 	initOrder()
 	initLineItem()
 }
 
-func DbGen(loaders map[Table]Loader, tables []Table) error {
-	for table, loader := range loaders {
-		tDefs[table].loader = loader
+func scaleDownTDefs(sc float64) {
+	intScale := dssHuge(1000 * sc)
+	for i := TPart; i < TNation; i++ {
+		tDefs[i].base = max(intScale*tDefs[i].base/1000, 1)
 	}
+}
 
+func DbGen(loaders map[Table]Loader, tables []Table) error {
+	return DbGenChunk(loaders, tables, 1, 1)
+}
+
+// DbGenChunk generates the chunk-th (starting from 1) of the chunks slices of the tables like
+// `dbgen -C chunks -S chunk`, the chunks can be generated concurrently and all of them make up
+// the whole tables. The nation and region tables are only generated in the first chunk.
+func DbGenChunk(loaders map[Table]Loader, tables []Table, chunk, chunks int) error {
 	for _, i := range tables {
-		fmt.Printf("generating %s\n", tDefs[i].comment)
-		rowCnt := tDefs[i].base
+		start, count := dssHuge(1), tDefs[i].base
 		if i < TNation {
-			rowCnt *= scale
+			start, count = chunkRows(count*scale, chunk, chunks)
+		} else if chunk > 1 {
+			continue
 		}
-		if err := genTbl(i, 1, rowCnt); err != nil {
+		if chunks > 1 {
+			fmt.Printf("generating %s chunk %d/%d\n", tDefs[i].comment, chunk, chunks)
+		} else {
+			fmt.Printf("generating %s\n", tDefs[i].comment)
+		}
+		if err := genTbl(tableLoader(loaders, i), i, start, count); err != nil {
 			return fmt.Errorf("fail to generate %s, err: %v", tDefs[i].name, err)
 		}
 		fmt.Printf("generate %s done\n", tDefs[i].comment)
 	}
 	return nil
 }
+
+// chunkRows returns the first row and the row count of the chunk, the chunk boundaries are proportional
+// to the row count, so the chunk i of n consists of the chunks (i-1)*m+1 to i*m of n*m.
+func chunkRows(rowCnt dssHuge, chunk, chunks int) (dssHuge, dssHuge) {
+	start := rowCnt * dssHuge(chunk-1) / dssHuge(chunks)
+	end := rowCnt * dssHuge(chunk) / dssHuge(chunks)
+	return start + 1, end - start
+}
+
+func tableLoader(loaders map[Table]Loader, t Table) Loader {
+	switch t {
+	case TOrderLine:
+		return newOrderLineLoader(loaders[TOrder], loaders[TLine])
+	case TPartPsupp:
+		return newPartPsuppLoader(loaders[TPart], loaders[TPsupp])
+	}
+	return loaders[t]
+}
@@ -189,20 +189,22 @@ var expectRegions = `
 #4|MIDDLE EAST|uickly special accounts cajole carefully blithely close requests. carefully final asymptotes haggle furiousl#
 `
 
+var loaders map[Table]Loader
+
 func TestMain(m *testing.M) {
 	InitDbGen(1)
 
 	// mock writer
-	tDefs[TOrder].loader = NewOrderLoader(&gotOrdersBuf)
-	tDefs[TLine].loader = NewLineItemLoader(&gotLinesBuf)
-	tDefs[TSupp].loader = NewSuppLoader(&gotSuppsBuf)
-	tDefs[TCust].loader = NewCustLoader(&gotCustsBuf)
-	tDefs[TPart].loader = NewPartLoader(&gotPartsBuf)
-	tDefs[TPsupp].loader = NewPartSuppLoader(&gotPartSuppsBuf)
-	tDefs[TNation].loader = NewNationLoader(&gotNationsBuf)
-	tDefs[TRegion].loader = NewRegionLoader(&gotRegionsBuf)
-	tDefs[TOrderLine].loader = newOrderLineLoader()
-	tDefs[TPartPsupp].loader = newPartPsuppLoader()
+	loaders = map[Table]Loader{
+		TOrder:  NewOrderLoader(&gotOrdersBuf),
+		TLine:   NewLineItemLoader(&gotLinesBuf),
+		TSupp:   NewSuppLoader(&gotSuppsBuf),
+		TCust:   NewCustLoader(&gotCustsBuf),
+		TPart:   NewPartLoader(&gotPartsBuf),
+		TPsupp:  NewPartSuppLoader(&gotPartSuppsBuf),
+		TNation: NewNationLoader(&gotNationsBuf),
+		TRegion: NewRegionLoader(&gotRegionsBuf),
+	}
 
 	os.Exit(m.Run())
 }
@@ -217,33 +219,75 @@ func whitespaceGuard(b bytes.Buffer) string {
 }
 
 func TestGenOrderLine(t *testing.T) {
-	assert.NoError(t, genTbl(TOrderLine, 1, 10))
+	assert.NoError(t, genTbl(tableLoader(loaders, TOrderLine), TOrderLine, 1, 10))
 	assert.Equal(t, strings.TrimSpace(expectOrders), whitespaceGuard(gotOrdersBuf))
 	assert.Equal(t, strings.TrimSpace(expectLines), whitespaceGuard(gotLinesBuf))
 }
 
 func TestGenSupp(t *testing.T) {
-	assert.NoError(t, genTbl(TSupp, 1, 10))
+	assert.NoError(t, genTbl(tableLoader(loaders, TSupp), TSupp, 1, 10))
 	assert.Equal(t, strings.TrimSpace(expectSupps), whitespaceGuard(gotSuppsBuf))
 }
 
 func TestGenCust(t *testing.T) {
-	assert.NoError(t, genTbl(TCust, 1, 10))
+	assert.NoError(t, genTbl(tableLoader(loaders, TCust), TCust, 1, 10))
 	assert.Equal(t, strings.TrimSpace(expectCusts), whitespaceGuard(gotCustsBuf))
 }
 
 func TestGenPartPsupp(t *testing.T) {
-	assert.NoError(t, genTbl(TPartPsupp, 1, 10))
+	assert.NoError(t, genTbl(tableLoader(loaders, TPartPsupp), TPartPsupp, 1, 10))
 	assert.Equal(t, strings.TrimSpace(expectParts), whitespaceGuard(gotPartsBuf))
 	assert.Equal(t, strings.TrimSpace(expectPartSupps), whitespaceGuard(gotPartSuppsBuf))
 }
 
 func TestGenNation(t *testing.T) {
-	assert.NoError(t, genTbl(TNation, 1, 25))
+	assert.NoError(t, genTbl(tableLoader(loaders, TNation), TNation, 1, 25))
 	assert.Equal(t, strings.TrimSpace(expectNations), whitespaceGuard(gotNationsBuf))
 }
 
 func TestGenRegion(t *testing.T) {
-	assert.NoError(t, genTbl(TRegion, 1, 5))
+	assert.NoError(t, genTbl(tableLoader(loaders, TRegion), TRegion, 1, 5))
 	assert.Equal(t, strings.TrimSpace(expectRegions), whitespaceGuard(gotRegionsBuf))
 }
+
+type itemCollector struct {
+	items []interface{}
+}
+
+func (c *itemCollector) Load(item interface{}) error {
+	c.items = append(c.items, item)
+	return nil
+}
+
+func (c *itemCollector) Flush() error {
+	return nil
+}
+
+func TestGenChunks(t *testing.T) {
+	const rows, chunks = 20, 3
+	for _, tnum := range []Table{TOrderLine, TPartPsupp, TSupp, TCust} {
+		expected := &itemCollector{}
+		assert.NoError(t, genTbl(expected, tnum, 1, rows))
+
+		got := &itemCollector{}
+		for chunk := 1; chunk <= chunks; chunk++ {
+			start, count := chunkRows(rows, chunk, chunks)
+			assert.NoError(t, genTbl(got, tnum, start, count))
+		}
+		assert.Equal(t, expected.items, got.items, tDefs[tnum].comment)
+	}
+}
+
+func TestChunkRows(t *testing.T) {
+	start, count := chunkRows(100, 3, 3)
+	assert.Equal(t, dssHuge(67), start)
+	assert.Equal(t, dssHuge(34), count)
+
+	// the chunk 2 of 3 is made up of the chunks 3 and 4 of 6
+	start, count = chunkRows(100, 2, 3)
+	start1, count1 := chunkRows(100, 3, 6)
+	start2, count2 := chunkRows(100, 4, 6)
+	assert.Equal(t, start, start1)
+	assert.Equal(t, start1+count1, start2)
+	assert.Equal(t, count, count1+count2)
+}
@@ -85,16 +85,16 @@ func NewLineItemLoader(w io.Writer) lineItemLoader {
 	return lineItemLoader{sink.NewCSVSinkWithDelimiter(w, '|')}
 }
 
-func sdLineItem(child Table, skipCount dssHuge) {
+func (g *generator) sdLineItem(child Table, skipCount dssHuge) {
 	for j := 0; j < oLcntMax; j++ {
 		for i := lQtySd; i <= lRflgSd; i++ {
-			advanceStream(i, skipCount, false)
+			g.advanceStream(i, skipCount, false)
 		}
-		advanceStream(lCmntSd, skipCount*2, false)
+		g.advanceStream(lCmntSd, skipCount*2, false)
 	}
 	if child == TPsupp {
-		advanceStream(oOdateSd, skipCount, false)
-		advanceStream(oLcntSd, skipCount, false)
+		g.advanceStream(oOdateSd, skipCount, false)
+		g.advanceStream(oLcntSd, skipCount, false)
 	}
 }
 
@@ -27,28 +27,30 @@ func makeAscDate() []string {
 	return res
 }
 
-func makeSparse(idx dssHuge) dssHuge {
-	return ((((idx >> 3) << 2) | (0 & 0x0003)) << 3) | (idx & 0x0007)
+// makeSparse keeps the low 3 bits of idx and inserts the 2 bits seq above them,
+// so the orders of refresh sets never collide with the loaded orders.
+func makeSparse(idx dssHuge, seq dssHuge) dssHuge {
+	return ((((idx >> 3) << 2) | (seq & 0x0003)) << 3) | (idx & 0x0007)
 }
 
-func pickStr(dist *distribution, c int, target *string) (pos int) {
-	j := long(random(1, dssHuge(dist.members[len(dist.members)-1].weight), long(c)))
+func (g *generator) pickStr(dist *distribution, c int, target *string) (pos int) {
+	j := long(g.random(1, dssHuge(dist.members[len(dist.members)-1].weight), long(c)))
 	for pos = 0; dist.members[pos].weight < j; pos++ {
 	}
 	*target = dist.members[pos].text
 	return
 }
 
-func pickClerk() string {
-	clkNum := random(1, max(scale*oClrkScl, oClrkScl), oClrkSd)
+func (g *generator) pickClerk() string {
+	clkNum := g.random(1, max(scale*oClrkScl, oClrkScl), oClrkSd)
 	return fmt.Sprintf("Clerk#%09d", clkNum)
 }
 
-func txtVp(sd int) string {
+func (g *generator) txtVp(sd int) string {
 	var src *distribution
 	var syntax string
 	var buf bytes.Buffer
-	pickStr(&vp, sd, &syntax)
+	g.pickStr(&vp, sd, &syntax)
 
 	for _, item := range strings.Split(syntax, " ") {
 		switch item[0] {
@@ -62,7 +64,7 @@ func txtVp(sd int) string {
 			panic("unreachable")
 		}
 		var tmp string
-		pickStr(src, sd, &tmp)
+		g.pickStr(src, sd, &tmp)
 		buf.WriteString(tmp)
 		if len(item) > 1 {
 			buf.Write([]byte{item[1]})
@@ -74,11 +76,11 @@ func txtVp(sd int) string {
 	return buf.String()
 }
 
-func txtNp(sd int) string {
+func (g *generator) txtNp(sd int) string {
 	var src *distribution
 	var syntax string
 	var buf bytes.Buffer
-	pickStr(&np, sd, &syntax)
+	g.pickStr(&np, sd, &syntax)
 
 	for _, item := range strings.Split(syntax, " ") {
 		switch item[0] {
@@ -94,7 +96,7 @@ func txtNp(sd int) string {
 			panic("unreachable")
 		}
 		var tmp string
-		pickStr(src, sd, &tmp)
+		g.pickStr(src, sd, &tmp)
 		buf.WriteString(tmp)
 		if len(item) > 1 {
 			buf.Write([]byte{item[1]})
@@ -105,23 +107,23 @@ func txtNp(sd int) string {
 	return buf.String()
 }
 
-func txtSentence(sd int) string {
+func (g *generator) txtSentence(sd int) string {
 	var syntax string
 	var buf bytes.Buffer
-	pickStr(&grammar, sd, &syntax)
+	g.pickStr(&grammar, sd, &syntax)
 
 	for _, item := range strings.Split(syntax, " ") {
 		switch item[0] {
 		case 'V':
-			buf.WriteString(txtVp(sd))
+			buf.WriteString(g.txtVp(sd))
 		case 'N':
-			buf.WriteString(txtNp(sd))
+			buf.WriteString(g.txtNp(sd))
 		case 'P':
 			var tmp string
-			pickStr(&prepositions, sd, &tmp)
+			g.pickStr(&prepositions, sd, &tmp)
 			buf.WriteString(tmp)
 			buf.WriteString(" the ")
-			buf.WriteString(txtNp(sd))
+			buf.WriteString(g.txtNp(sd))
 		case 'T':
 			sentence := buf.String()
 			sentence = sentence[0 : len(sentence)-1]
@@ -129,7 +131,7 @@ func txtSentence(sd int) string {
 			buf.WriteString(sentence)
 
 			var tmp string
-			pickStr(&terminators, sd, &tmp)
+			g.pickStr(&terminators, sd, &tmp)
 			buf.WriteString(tmp)
 		default:
 			panic("unreachable")
@@ -141,22 +143,22 @@ func txtSentence(sd int) string {
 	return buf.String()
 }
 
-func makeText(avg, sd int) string {
+func (g *generator) makeText(avg, sd int) string {
 	min := int(float64(avg) * vStrLow)
 	max := int(float64(avg) * vStrHgh)
 
-	hgOffset := random(0, dssHuge(textPoolSize-max), long(sd))
-	hgLength := random(dssHuge(min), dssHuge(max), long(sd))
+	hgOffset := g.random(0, dssHuge(textPoolSize-max), long(sd))
+	hgLength := g.random(dssHuge(min), dssHuge(max), long(sd))
 
 	return string(szTextPool[hgOffset : hgOffset+hgLength])
 }
 
-func aggStr(set *distribution, count, col long) string {
+func (g *generator) aggStr(set *distribution, count, col long) string {
 	var buf bytes.Buffer
-	permuteDist(set, col)
+	perm := g.permuteDist(set, col)
 
 	for i := long(0); i < count; i++ {
-		buf.WriteString(set.members[set.permute[i]].text)
+		buf.WriteString(set.members[perm[i]].text)
 		buf.WriteString(" ")
 	}
 
@@ -171,6 +173,11 @@ func rpbRoutine(p dssHuge) dssHuge {
 	return price
 }
 
+// RetailPrice returns the retail price of the part in cents.
+func RetailPrice(partKey int64) int64 {
+	return int64(rpbRoutine(dssHuge(partKey)))
+}
+
 func min(a, b dssHuge) dssHuge {
 	if a < b {
 		return a
@@ -219,14 +226,15 @@ func FmtMoney(m dssHuge) string {
 	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
 }
 
-func sdNull(_ Table, _ dssHuge) {
+func (g *generator) sdNull(_ Table, _ dssHuge) {
 }
 
 func initTextPool() {
 	var buffer bytes.Buffer
 
+	g := newGenerator()
 	for buffer.Len() < textPoolSize {
-		sentence := txtSentence(5)
+		sentence := g.txtSentence(5)
 		len := len(sentence)
 
 		needed := textPoolSize - buffer.Len()
@@ -20,12 +20,12 @@ type Nation struct {
 	Comment string
 }
 
-func makeNation(idx dssHuge) *Nation {
+func (g *generator) makeNation(idx dssHuge) *Nation {
 	nation := &Nation{}
 	nation.Code = idx - 1
 	nation.Text = nations.members[idx-1].text
 	nation.Join = nations.members[idx-1].weight
-	nation.Comment = makeText(nCmntLen, nCmntSd)
+	nation.Comment = g.makeText(nCmntLen, nCmntSd)
 
 	return nation
 }
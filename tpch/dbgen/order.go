@@ -87,24 +87,24 @@ func NewOrderLoader(w io.Writer) orderLoader {
 	return orderLoader{sink.NewCSVSinkWithDelimiter(w, '|')}
 }
 
-func sdOrder(child Table, skipCount dssHuge) {
-	advanceStream(oLcntSd, skipCount, false)
-	advanceStream(oCkeySd, skipCount, false)
-	advanceStream(oCmntSd, skipCount*2, false)
-	advanceStream(oSuppSd, skipCount, false)
-	advanceStream(oClrkSd, skipCount, false)
-	advanceStream(oPrioSd, skipCount, false)
-	advanceStream(oOdateSd, skipCount, false)
+func (g *generator) sdOrder(child Table, skipCount dssHuge) {
+	g.advanceStream(oLcntSd, skipCount, false)
+	g.advanceStream(oCkeySd, skipCount, false)
+	g.advanceStream(oCmntSd, skipCount*2, false)
+	g.advanceStream(oSuppSd, skipCount, false)
+	g.advanceStream(oClrkSd, skipCount, false)
+	g.advanceStream(oPrioSd, skipCount, false)
+	g.advanceStream(oOdateSd, skipCount, false)
 }
 
-func makeOrder(idx dssHuge) *Order {
+func (g *generator) makeOrder(idx dssHuge, seq dssHuge) *Order {
 	delta := 1
 	order := &Order{}
-	order.OKey = makeSparse(idx)
+	order.OKey = makeSparse(idx, seq)
 	if scale >= 30000 {
-		order.CustKey = random64(ockeyMin, ockeyMax, oCkeySd)
+		order.CustKey = g.random64(ockeyMin, ockeyMax, oCkeySd)
 	} else {
-		order.CustKey = random(ockeyMin, ockeyMax, oCkeySd)
+		order.CustKey = g.random(ockeyMin, ockeyMax, oCkeySd)
 	}
 
 	// Comment: Orders are not present for all customers.
@@ -115,50 +115,50 @@ func makeOrder(idx dssHuge) *Order {
 		order.CustKey = min(order.CustKey, ockeyMax)
 		delta *= -1
 	}
-	tmpDate := random(odateMin, odateMax, oOdateSd)
+	tmpDate := g.random(odateMin, odateMax, oOdateSd)
 	order.Date = ascDate[tmpDate-startDate]
-	pickStr(&oPrioritySet, oPrioSd, &order.OrderPriority)
-	order.Clerk = pickClerk()
-	order.Comment = makeText(oCmntLen, oCmntSd)
+	g.pickStr(&oPrioritySet, oPrioSd, &order.OrderPriority)
+	order.Clerk = g.pickClerk()
+	order.Comment = g.makeText(oCmntLen, oCmntSd)
 	order.ShipPriority = 0
 	order.TotalPrice = 0
 	order.Status = "O"
 	oCnt := 0
-	lineCount := random(oLcntMin, oLcntMax, oLcntSd)
+	lineCount := g.random(oLcntMin, oLcntMax, oLcntSd)
 
 	for lCnt := dssHuge(0); lCnt < lineCount; lCnt++ {
 		line := LineItem{}
 		line.OKey = order.OKey
 		line.LCnt = lCnt + 1
-		line.Quantity = random(lQtyMin, lQtyMax, lQtySd)
-		line.Discount = random(lDcntMin, lDcntMax, lDcntSd)
-		line.Tax = random(lTaxMin, lTaxMax, lTaxSd)
+		line.Quantity = g.random(lQtyMin, lQtyMax, lQtySd)
+		line.Discount = g.random(lDcntMin, lDcntMax, lDcntSd)
+		line.Tax = g.random(lTaxMin, lTaxMax, lTaxSd)
 
-		pickStr(&lInstructSet, lShipSd, &line.ShipInstruct)
-		pickStr(&lSmodeSet, lSmodeSd, &line.ShipMode)
-		line.Comment = makeText(lCmntLen, lCmntSd)
+		g.pickStr(&lInstructSet, lShipSd, &line.ShipInstruct)
+		g.pickStr(&lSmodeSet, lSmodeSd, &line.ShipMode)
+		line.Comment = g.makeText(lCmntLen, lCmntSd)
 
 		if scale > 30000 {
-			line.PartKey = random64(lPkeyMin, LPkeyMax, lPkeySd)
+			line.PartKey = g.random64(lPkeyMin, LPkeyMax, lPkeySd)
 		} else {
-			line.PartKey = random(lPkeyMin, LPkeyMax, lPkeySd)
+			line.PartKey = g.random(lPkeyMin, LPkeyMax, lPkeySd)
 		}
 
 		rPrice := rpbRoutine(line.PartKey)
-		suppNum := random(0, 3, lSkeySd)
+		suppNum := g.random(0, 3, lSkeySd)
 		line.SuppKey = partSuppBridge(line.PartKey, suppNum)
 		line.EPrice = rPrice * line.Quantity
 
 		order.TotalPrice += ((line.EPrice * (100 - line.Discount)) / pennies) *
 			(100 + line.Tax) / pennies
 
-		sDate := random(lSdteMin, lSdteMax, lSdteSd)
+		sDate := g.random(lSdteMin, lSdteMax, lSdteSd)
 		sDate += tmpDate
 
-		cDate := random(lCdteMin, lCdteMax, lCdteSd)
+		cDate := g.random(lCdteMin, lCdteMax, lCdteSd)
 		cDate += tmpDate
 
-		rDate := random(lRdteMin, lRdteMax, lRdteSd)
+		rDate := g.random(lRdteMin, lRdteMax, lRdteSd)
 		rDate += sDate
 		line.SDate = ascDate[sDate-startDate]
 		line.CDate = ascDate[cDate-startDate]
@@ -166,7 +166,7 @@ func makeOrder(idx dssHuge) *Order {
 
 		if julian(int(rDate)) <= currentDate {
 			var tmpStr string
-			pickStr(&lRflagSet, lRflgSd, &tmpStr)
+			g.pickStr(&lRflagSet, lRflgSd, &tmpStr)
 			line.RFlag = tmpStr[0:1]
 		} else {
 			line.RFlag = "N"
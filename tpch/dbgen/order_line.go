@@ -5,28 +5,30 @@ type OrderLine struct {
 }
 
 type orderLineLoader struct {
+	orders Loader
+	lines  Loader
 }
 
 func (o orderLineLoader) Load(item interface{}) error {
-	if err := tDefs[TOrder].loader.Load(item); err != nil {
+	if err := o.orders.Load(item); err != nil {
 		return err
 	}
-	if err := tDefs[TLine].loader.Load(item); err != nil {
+	if err := o.lines.Load(item); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (o orderLineLoader) Flush() error {
-	if err := tDefs[TOrder].loader.Flush(); err != nil {
+	if err := o.orders.Flush(); err != nil {
 		return nil
 	}
-	if err := tDefs[TLine].loader.Flush(); err != nil {
+	if err := o.lines.Flush(); err != nil {
 		return err
 	}
 	return nil
 }
 
-func newOrderLineLoader() orderLineLoader {
-	return orderLineLoader{}
+func newOrderLineLoader(orders, lines Loader) orderLineLoader {
+	return orderLineLoader{orders: orders, lines: lines}
 }
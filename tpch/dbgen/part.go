@@ -48,12 +48,12 @@ type Part struct {
 	S           []PartSupp
 }
 
-func sdPart(child Table, skipCount dssHuge) {
+func (g *generator) sdPart(child Table, skipCount dssHuge) {
 	for i := pMfgSd; i <= pCntrSd; i++ {
-		advanceStream(i, skipCount, false)
+		g.advanceStream(i, skipCount, false)
 	}
-	advanceStream(pCmntSd, skipCount*2, false)
-	advanceStream(pNameSd, skipCount*92, false)
+	g.advanceStream(pCmntSd, skipCount*2, false)
+	g.advanceStream(pNameSd, skipCount*92, false)
 }
 
 func partSuppBridge(p, s dssHuge) dssHuge {
@@ -90,27 +90,27 @@ func NewPartLoader(w io.Writer) partLoader {
 	return partLoader{sink.NewCSVSinkWithDelimiter(w, '|')}
 }
 
-func makePart(idx dssHuge) *Part {
+func (g *generator) makePart(idx dssHuge) *Part {
 	part := &Part{}
 	part.PartKey = idx
-	part.Name = aggStr(&colors, pNameScl, pNameSd)
-	tmp := random(pMfgMin, pMfgMax, pMfgSd)
+	part.Name = g.aggStr(&colors, pNameScl, pNameSd)
+	tmp := g.random(pMfgMin, pMfgMax, pMfgSd)
 	part.Mfgr = fmt.Sprintf("Manufacturer#%d", tmp)
-	brnd := random(pBrndMin, pBrndMax, pBrndSd)
+	brnd := g.random(pBrndMin, pBrndMax, pBrndSd)
 	part.Brand = fmt.Sprintf("Brand#%02d", tmp*10+brnd)
-	pickStr(&pTypesSet, pTypeSd, &part.Type)
-	part.Size = random(pSizeMin, pSizeMax, pSizeSd)
-	pickStr(&pCntrSet, pCntrSd, &part.Container)
+	g.pickStr(&pTypesSet, pTypeSd, &part.Type)
+	part.Size = g.random(pSizeMin, pSizeMax, pSizeSd)
+	g.pickStr(&pCntrSet, pCntrSd, &part.Container)
 	part.RetailPrice = rpbRoutine(idx)
-	part.Comment = makeText(pCmntLen, pCmntSd)
+	part.Comment = g.makeText(pCmntLen, pCmntSd)
 
 	for snum := 0; snum < suppPerPart; snum++ {
 		ps := PartSupp{}
 		ps.PartKey = part.PartKey
 		ps.SuppKey = partSuppBridge(idx, dssHuge(snum))
-		ps.Qty = random(psQtyMin, psQtyMax, psQtySd)
-		ps.SCost = random(psScstMin, psScstMax, psScstSd)
-		ps.Comment = makeText(psCmntLen, psCmntSd)
+		ps.Qty = g.random(psQtyMin, psQtyMax, psQtySd)
+		ps.SCost = g.random(psScstMin, psScstMax, psScstSd)
+		ps.Comment = g.makeText(psCmntLen, psCmntSd)
 		part.S = append(part.S, ps)
 	}
 
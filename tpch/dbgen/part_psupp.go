@@ -1,27 +1,30 @@
 package dbgen
 
-type partPsuppLoader struct{}
+type partPsuppLoader struct {
+	parts     Loader
+	partSupps Loader
+}
 
 func (p partPsuppLoader) Load(item interface{}) error {
-	if err := tDefs[TPart].loader.Load(item); err != nil {
+	if err := p.parts.Load(item); err != nil {
 		return err
 	}
-	if err := tDefs[TPsupp].loader.Load(item); err != nil {
+	if err := p.partSupps.Load(item); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (p partPsuppLoader) Flush() error {
-	if err := tDefs[TPart].loader.Flush(); err != nil {
+	if err := p.parts.Flush(); err != nil {
 		return err
 	}
-	if err := tDefs[TPsupp].loader.Flush(); err != nil {
+	if err := p.partSupps.Flush(); err != nil {
 		return err
 	}
 	return nil
 }
 
-func newPartPsuppLoader() partPsuppLoader {
-	return partPsuppLoader{}
+func newPartPsuppLoader(parts, partSupps Loader) partPsuppLoader {
+	return partPsuppLoader{parts: parts, partSupps: partSupps}
 }
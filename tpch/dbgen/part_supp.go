@@ -15,11 +15,11 @@ type PartSupp struct {
 	Comment string
 }
 
-func sdPsupp(child Table, skipCount dssHuge) {
+func (g *generator) sdPsupp(child Table, skipCount dssHuge) {
 	for j := 0; j < suppPerPart; j++ {
-		advanceStream(psQtySd, skipCount, false)
-		advanceStream(psScstSd, skipCount, false)
-		advanceStream(psCmntSd, skipCount*2, false)
+		g.advanceStream(psQtySd, skipCount, false)
+		g.advanceStream(psScstSd, skipCount, false)
+		g.advanceStream(psCmntSd, skipCount*2, false)
 	}
 }
 
@@ -0,0 +1,180 @@
+package dbgen
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/go-tpc/tpch/dbgen/dist"
+)
+
+var (
+	// qgenWords1 and qgenWords2 are the words of the o_comment pattern of Q13.
+	qgenWords1 = []string{"special", "pending", "unusual", "express"}
+	qgenWords2 = []string{"packages", "requests", "accounts", "deposits"}
+
+	qgenStartDate = time.Date(1993, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// QGen generates the substitution parameters of the queries like qgen, refer to
+// Clause 2.4 of the spec. The parameters of a query stream are reproducible by the seed.
+type QGen struct {
+	rand  *rand.Rand
+	scale float64
+}
+
+// NewQGen returns the parameter generator of a query stream, usually the seed is
+// the base seed of the run plus the stream number.
+func NewQGen(seed int64, scale float64) *QGen {
+	return &QGen{rand: rand.New(rand.NewSource(seed)), scale: scale}
+}
+
+// Params returns the substitution parameters :1, :2, ... of the query, it returns nil if the query doesn't exist.
+func (g *QGen) Params(query int) []string {
+	switch query {
+	case 1:
+		return []string{g.intStr(60, 120)}
+	case 2:
+		return []string{g.intStr(1, 50), g.pick(syllables("p_types", 2)), g.pick(distTexts("regions"))}
+	case 3:
+		return []string{g.pick(distTexts("msegmnt")), g.date(time.Date(1995, 3, g.intn(1, 31), 0, 0, 0, 0, time.UTC))}
+	case 4:
+		return []string{g.month(0, 57)}
+	case 5:
+		return []string{g.pick(distTexts("regions")), g.year()}
+	case 6:
+		return []string{g.year(), fmt.Sprintf("0.%02d", g.intn(2, 9)), g.intStr(24, 25)}
+	case 7:
+		nations := g.distinct(distTexts("nations"), 2)
+		return []string{nations[0], nations[1]}
+	case 8:
+		nation, region := g.nation()
+		return []string{nation, region, g.partType(3)}
+	case 9:
+		return []string{g.pick(distTexts("colors"))}
+	case 10:
+		return []string{g.month(1, 24)}
+	case 11:
+		return []string{g.pick(distTexts("nations")), strconv.FormatFloat(0.0001/g.scale, 'f', 10, 64)}
+	case 12:
+		modes := g.distinct(distTexts("smode"), 2)
+		return []string{modes[0], modes[1], g.year()}
+	case 13:
+		return []string{g.pick(qgenWords1), g.pick(qgenWords2)}
+	case 14:
+		return []string{g.month(0, 59)}
+	case 15:
+		return []string{g.month(0, 57)}
+	case 16:
+		params := []string{g.brand(), g.partType(2)}
+		for _, size := range g.rand.Perm(50)[:8] {
+			params = append(params, strconv.Itoa(size+1))
+		}
+		return params
+	case 17:
+		return []string{g.brand(), g.pick(syllables("p_cntr", 0)) + " " + g.pick(syllables("p_cntr", 1))}
+	case 18:
+		return []string{g.intStr(312, 315)}
+	case 19:
+		return []string{g.brand(), g.brand(), g.brand(), g.intStr(1, 10), g.intStr(10, 20), g.intStr(20, 30)}
+	case 20:
+		return []string{g.pick(distTexts("colors")), g.year(), g.pick(distTexts("nations"))}
+	case 21:
+		return []string{g.pick(distTexts("nations"))}
+	case 22:
+		var codes []string
+		for _, key := range g.rand.Perm(len(dist.Maps["nations"]))[:7] {
+			codes = append(codes, strconv.Itoa(key+10))
+		}
+		return codes
+	}
+	return nil
+}
+
+// intn returns a random integer in [min, max].
+func (g *QGen) intn(min, max int) int {
+	return min + g.rand.Intn(max-min+1)
+}
+
+func (g *QGen) intStr(min, max int) string {
+	return strconv.Itoa(g.intn(min, max))
+}
+
+func (g *QGen) pick(texts []string) string {
+	return texts[g.rand.Intn(len(texts))]
+}
+
+func (g *QGen) distinct(texts []string, n int) []string {
+	res := make([]string, 0, n)
+	for _, i := range g.rand.Perm(len(texts))[:n] {
+		res = append(res, texts[i])
+	}
+	return res
+}
+
+func (g *QGen) date(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// year returns the first day of a random year in [1993, 1997].
+func (g *QGen) year() string {
+	return g.date(qgenStartDate.AddDate(g.intn(0, 4), 0, 0))
+}
+
+// month returns the first day of a random month in [min, max] months after 1993-01-01.
+func (g *QGen) month(min, max int) string {
+	return g.date(qgenStartDate.AddDate(0, g.intn(min, max), 0))
+}
+
+func (g *QGen) brand() string {
+	return fmt.Sprintf("Brand#%d%d", g.intn(1, 5), g.intn(1, 5))
+}
+
+// partType returns the first n syllables of a random part type.
+func (g *QGen) partType(n int) string {
+	words := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		words = append(words, g.pick(syllables("p_types", i)))
+	}
+	return strings.Join(words, " ")
+}
+
+// nation returns a random nation and its region, the weights of the nations accumulate to their region keys.
+func (g *QGen) nation() (string, string) {
+	var (
+		nations = dist.Maps["nations"]
+		idx     = g.rand.Intn(len(nations))
+		region  int32
+	)
+	for _, nation := range nations[:idx+1] {
+		region += nation.Weight
+	}
+	return nations[idx].Text, dist.Maps["regions"][region].Text
+}
+
+func distTexts(name string) []string {
+	items := dist.Maps[name]
+	texts := make([]string, 0, len(items))
+	for _, item := range items {
+		texts = append(texts, item.Text)
+	}
+	return texts
+}
+
+// syllables returns the distinct i-th words of the distribution in order.
+func syllables(name string, i int) []string {
+	var (
+		words []string
+		seen  = make(map[string]struct{})
+	)
+	for _, item := range dist.Maps[name] {
+		word := strings.Fields(item.Text)[i]
+		if _, ok := seen[word]; !ok {
+			seen[word] = struct{}{}
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,57 @@
+package dbgen
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQGenReproducible(t *testing.T) {
+	a, b := NewQGen(42, 1), NewQGen(42, 1)
+	for q := 1; q <= 22; q++ {
+		assert.Equal(t, a.Params(q), b.Params(q), "q%d", q)
+	}
+	assert.Nil(t, a.Params(23))
+}
+
+func TestQGenParams(t *testing.T) {
+	g := NewQGen(1, 10)
+	for i := 0; i < 100; i++ {
+		delta, _ := strconv.Atoi(g.Params(1)[0])
+		assert.True(t, delta >= 60 && delta <= 120)
+
+		q7 := g.Params(7)
+		assert.NotEqual(t, q7[0], q7[1])
+
+		q8 := g.Params(8)
+		assert.Contains(t, []string{"AFRICA", "AMERICA", "ASIA", "EUROPE", "MIDDLE EAST"}, q8[1])
+
+		q10 := g.Params(10)[0]
+		assert.True(t, q10 >= "1993-02-01" && q10 <= "1995-01-01", q10)
+
+		sizes := make(map[string]struct{})
+		for _, size := range g.Params(16)[2:] {
+			sizes[size] = struct{}{}
+		}
+		assert.Len(t, sizes, 8)
+
+		q18, _ := strconv.Atoi(g.Params(18)[0])
+		assert.True(t, q18 >= 312 && q18 <= 315)
+
+		for _, code := range g.Params(22) {
+			c, _ := strconv.Atoi(code)
+			assert.True(t, c >= 10 && c <= 34)
+		}
+	}
+	assert.Equal(t, "0.0000100000", g.Params(11)[1])
+
+	regions := map[string]string{}
+	for i := 0; i < 1000; i++ {
+		nation, region := g.nation()
+		regions[nation] = region
+	}
+	assert.Equal(t, "MIDDLE EAST", regions["EGYPT"])
+	assert.Equal(t, "ASIA", regions["JAPAN"])
+	assert.Equal(t, "AMERICA", regions["UNITED STATES"])
+}
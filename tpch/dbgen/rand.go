@@ -29,7 +29,16 @@ type Seed struct {
 	boundary dssHuge
 }
 
-var seeds [maxStream + 1]Seed
+// generator holds the random number streams, every chunk generated concurrently has its own generator.
+type generator struct {
+	seeds [maxStream + 1]Seed
+	perm  []long
+}
+
+// newGenerator returns a generator whose streams start from the first row of every table.
+func newGenerator() *generator {
+	return &generator{seeds: initialSeeds}
+}
 
 func nextRand(nSeed dssHuge) dssHuge {
 	return (nSeed * 16807) % 2147483647
@@ -41,7 +50,7 @@ func nextRand64(nSeed dssHuge) dssHuge {
 	return nSeed*a + c
 }
 
-func unifInt(nLow dssHuge, nHigh dssHuge, nStream long) dssHuge {
+func (g *generator) unifInt(nLow dssHuge, nHigh dssHuge, nStream long) dssHuge {
 	var dRange float64
 	var nTemp dssHuge
 	nLow32 := int32(nLow)
@@ -57,12 +66,12 @@ func unifInt(nLow dssHuge, nHigh dssHuge, nStream long) dssHuge {
 		dRange = float64(nHigh - nLow + 1)
 		_ = nHigh - nLow + 1
 	}
-	seeds[nStream].value = nextRand(seeds[nStream].value)
-	nTemp = dssHuge(float64(seeds[nStream].value) / dM * dRange)
+	g.seeds[nStream].value = nextRand(g.seeds[nStream].value)
+	nTemp = dssHuge(float64(g.seeds[nStream].value) / dM * dRange)
 	return nLow + nTemp
 }
 
-func random64(lower, upper dssHuge, nStream long) dssHuge {
+func (g *generator) random64(lower, upper dssHuge, nStream long) dssHuge {
 
 	if nStream < 0 || nStream > maxStream {
 		nStream = 0
@@ -70,20 +79,20 @@ func random64(lower, upper dssHuge, nStream long) dssHuge {
 	if lower > upper {
 		lower, upper = upper, lower
 	}
-	seeds[nStream].value = nextRand64(seeds[nStream].value)
+	g.seeds[nStream].value = nextRand64(g.seeds[nStream].value)
 
-	nTemp := seeds[nStream].value
+	nTemp := g.seeds[nStream].value
 	if nTemp < 0 {
 		nTemp = -nTemp
 	}
 	nTemp %= upper - lower + 1
-	seeds[nStream].usage += 1
+	g.seeds[nStream].usage += 1
 	return lower + nTemp
 }
 
-func random(lower, upper dssHuge, nStream long) dssHuge {
-	seeds[nStream].usage += 1
-	return unifInt(lower, upper, nStream)
+func (g *generator) random(lower, upper dssHuge, nStream long) dssHuge {
+	g.seeds[nStream].usage += 1
+	return g.unifInt(lower, upper, nStream)
 }
 
 func advanceRand64(nSeed, nCount dssHuge) dssHuge {
@@ -129,20 +138,20 @@ func nthElement(n dssHuge, startSeed *dssHuge) {
 	*startSeed = z
 }
 
-func advanceStream(nStream int, nCalls dssHuge, bUse64Bit bool) {
+func (g *generator) advanceStream(nStream int, nCalls dssHuge, bUse64Bit bool) {
 	if bUse64Bit {
-		seeds[nStream].value = advanceRand64(seeds[nStream].value, nCalls)
+		g.seeds[nStream].value = advanceRand64(g.seeds[nStream].value, nCalls)
 	} else {
-		nthElement(nCalls, &seeds[nStream].value)
+		nthElement(nCalls, &g.seeds[nStream].value)
 	}
 }
 
-func rowStart(_ Table) {
+func (g *generator) rowStart(_ Table) {
 	for i := 0; i < maxStream; i++ {
-		seeds[i].usage = 0
+		g.seeds[i].usage = 0
 	}
 }
-func rowStop(t Table) {
+func (g *generator) rowStop(t Table) {
 	if t == TOrderLine {
 		t = TOrder
 	}
@@ -151,19 +160,19 @@ func rowStop(t Table) {
 	}
 
 	for i := 0; i < maxStream; i++ {
-		if seeds[i].Table == t || seeds[i].Table == tDefs[t].child {
-			nthElement(seeds[i].boundary-seeds[i].usage, &seeds[i].value)
+		if g.seeds[i].Table == t || g.seeds[i].Table == tDefs[t].child {
+			nthElement(g.seeds[i].boundary-g.seeds[i].usage, &g.seeds[i].value)
 		}
 	}
 }
 
-func aRand(min, max, column int) string {
+func (g *generator) aRand(min, max, column int) string {
 	var buf bytes.Buffer
 	var charInt dssHuge
-	len := random(dssHuge(min), dssHuge(max), long(column))
+	len := g.random(dssHuge(min), dssHuge(max), long(column))
 	for i := dssHuge(0); i < len; i++ {
 		if i%5 == 0 {
-			charInt = random(0, maxLong, long(column))
+			charInt = g.random(0, maxLong, long(column))
 		}
 		buf.Write([]byte{alphaNum[charInt&0o77]})
 		charInt >>= 6
@@ -171,14 +180,14 @@ func aRand(min, max, column int) string {
 	return buf.String()
 }
 
-func vStr(avg, sd int) string {
-	return aRand((int)(float64(avg)*vStrLow), (int)(float64(avg)*vStrHgh), sd)
+func (g *generator) vStr(avg, sd int) string {
+	return g.aRand((int)(float64(avg)*vStrLow), (int)(float64(avg)*vStrHgh), sd)
 }
 
-func genPhone(idx dssHuge, sd int) string {
-	aCode := random(100, 999, long(sd))
-	exChg := random(100, 999, long(sd))
-	number := random(1000, 9999, long(sd))
+func (g *generator) genPhone(idx dssHuge, sd int) string {
+	aCode := g.random(100, 999, long(sd))
+	exChg := g.random(100, 999, long(sd))
+	number := g.random(1000, 9999, long(sd))
 
 	return fmt.Sprintf("%02d-%03d-%03d-%04d",
 		10+(idx%nationsMax),
@@ -187,55 +196,53 @@ func genPhone(idx dssHuge, sd int) string {
 		number)
 }
 
-func initSeeds() {
-	seeds = [maxStream + 1]Seed{
-		{TPart, 1, 0, 1},
-		{TPart, 46831694, 0, 1},
-		{TPart, 1841581359, 0, 1},
-		{TPart, 1193163244, 0, 1},
-		{TPart, 727633698, 0, 1},
-		{TNone, 933588178, 0, 1},
-		{TPart, 804159733, 0, 2},
-		{TPsupp, 1671059989, 0, suppPerPart},
-		{TPsupp, 1051288424, 0, suppPerPart},
-		{TPsupp, 1961692154, 0, suppPerPart * 2},
-		{TOrder, 1227283347, 0, 1},
-		{TOrder, 1171034773, 0, 1},
-		{TOrder, 276090261, 0, 2},
-		{TOrder, 1066728069, 0, 1},
-		{TLine, 209208115, 0, oLcntMax},
-		{TLine, 554590007, 0, oLcntMax},
-		{TLine, 721958466, 0, oLcntMax},
-		{TLine, 1371272478, 0, oLcntMax},
-		{TLine, 675466456, 0, oLcntMax},
-		{TLine, 1808217256, 0, oLcntMax},
-		{TLine, 2095021727, 0, oLcntMax},
-		{TLine, 1769349045, 0, oLcntMax},
-		{TLine, 904914315, 0, oLcntMax},
-		{TLine, 373135028, 0, oLcntMax},
-		{TLine, 717419739, 0, oLcntMax},
-		{TLine, 1095462486, 0, oLcntMax * 2},
-		{TCust, 881155353, 0, 9},
-		{TCust, 1489529863, 0, 1},
-		{TCust, 1521138112, 0, 3},
-		{TCust, 298370230, 0, 1},
-		{TCust, 1140279430, 0, 1},
-		{TCust, 1335826707, 0, 2},
-		{TSupp, 706178559, 0, 9},
-		{TSupp, 110356601, 0, 1},
-		{TSupp, 884434366, 0, 3},
-		{TSupp, 962338209, 0, 1},
-		{TSupp, 1341315363, 0, 2},
-		{TPart, 709314158, 0, 92},
-		{TOrder, 591449447, 0, 1},
-		{TLine, 431918286, 0, 1},
-		{TOrder, 851767375, 0, 1},
-		{TNation, 606179079, 0, 2},
-		{TRegion, 1500869201, 0, 2},
-		{TOrder, 1434868289, 0, 1},
-		{TSupp, 263032577, 0, 1},
-		{TSupp, 753643799, 0, 1},
-		{TSupp, 202794285, 0, 1},
-		{TSupp, 715851524, 0, 1},
-	}
+var initialSeeds = [maxStream + 1]Seed{
+	{TPart, 1, 0, 1},
+	{TPart, 46831694, 0, 1},
+	{TPart, 1841581359, 0, 1},
+	{TPart, 1193163244, 0, 1},
+	{TPart, 727633698, 0, 1},
+	{TNone, 933588178, 0, 1},
+	{TPart, 804159733, 0, 2},
+	{TPsupp, 1671059989, 0, suppPerPart},
+	{TPsupp, 1051288424, 0, suppPerPart},
+	{TPsupp, 1961692154, 0, suppPerPart * 2},
+	{TOrder, 1227283347, 0, 1},
+	{TOrder, 1171034773, 0, 1},
+	{TOrder, 276090261, 0, 2},
+	{TOrder, 1066728069, 0, 1},
+	{TLine, 209208115, 0, oLcntMax},
+	{TLine, 554590007, 0, oLcntMax},
+	{TLine, 721958466, 0, oLcntMax},
+	{TLine, 1371272478, 0, oLcntMax},
+	{TLine, 675466456, 0, oLcntMax},
+	{TLine, 1808217256, 0, oLcntMax},
+	{TLine, 2095021727, 0, oLcntMax},
+	{TLine, 1769349045, 0, oLcntMax},
+	{TLine, 904914315, 0, oLcntMax},
+	{TLine, 373135028, 0, oLcntMax},
+	{TLine, 717419739, 0, oLcntMax},
+	{TLine, 1095462486, 0, oLcntMax * 2},
+	{TCust, 881155353, 0, 9},
+	{TCust, 1489529863, 0, 1},
+	{TCust, 1521138112, 0, 3},
+	{TCust, 298370230, 0, 1},
+	{TCust, 1140279430, 0, 1},
+	{TCust, 1335826707, 0, 2},
+	{TSupp, 706178559, 0, 9},
+	{TSupp, 110356601, 0, 1},
+	{TSupp, 884434366, 0, 3},
+	{TSupp, 962338209, 0, 1},
+	{TSupp, 1341315363, 0, 2},
+	{TPart, 709314158, 0, 92},
+	{TOrder, 591449447, 0, 1},
+	{TLine, 431918286, 0, 1},
+	{TOrder, 851767375, 0, 1},
+	{TNation, 606179079, 0, 2},
+	{TRegion, 1500869201, 0, 2},
+	{TOrder, 1434868289, 0, 1},
+	{TSupp, 263032577, 0, 1},
+	{TSupp, 753643799, 0, 1},
+	{TSupp, 202794285, 0, 1},
+	{TSupp, 715851524, 0, 1},
 }
@@ -0,0 +1,50 @@
+package dbgen
+
+// updPct is the refresh percentage in units of 0.01%, every refresh set
+// inserts and deletes 0.1% of the orders.
+const updPct = 10
+
+func refreshRows() dssHuge {
+	return tDefs[TOrder].base / 10000 * scale * updPct
+}
+
+// RefreshRows returns the number of orders inserted by RF1 or deleted by RF2 in one refresh set.
+func RefreshRows() int64 {
+	return int64(refreshRows())
+}
+
+// DbGenRefresh generates the new orders and their lineitems of the refresh set
+// (starting from 1) to loader, which are inserted by RF1. It is the equivalent
+// of the orders.tbl.u<set> and lineitem.tbl.u<set> of `dbgen -U`, the random
+// streams continue from the loaded orders and the previous refresh sets.
+func DbGenRefresh(set int64, loader Loader) error {
+	defer loader.Flush()
+
+	rows := refreshRows()
+	g := newGenerator()
+	g.skipRows(TOrderLine, tDefs[TOrder].base*scale+dssHuge(set-1)*rows)
+
+	seq := 1 + dssHuge(set)/(10000/updPct)
+	start := dssHuge(set-1)*rows + 1
+	for i := start; i < start+rows; i++ {
+		g.rowStart(TOrderLine)
+		if err := loader.Load(g.makeOrder(i, seq)); err != nil {
+			return err
+		}
+		g.rowStop(TOrderLine)
+	}
+	return nil
+}
+
+// RefreshDeleteKeys returns the keys of the orders deleted by RF2 in the refresh
+// set (starting from 1). It is the equivalent of the delete.<set> of `dbgen -U`.
+func RefreshDeleteKeys(set int64) []int64 {
+	rows := refreshRows()
+	seq := dssHuge(set-1) / (10000 / updPct)
+	start := dssHuge(set-1)*rows + 1
+	keys := make([]int64, 0, rows)
+	for i := start; i < start+rows; i++ {
+		keys = append(keys, int64(makeSparse(i, seq)))
+	}
+	return keys
+}
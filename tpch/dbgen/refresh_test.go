@@ -0,0 +1,70 @@
+package dbgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCollector struct {
+	orders []*Order
+}
+
+func (c *orderCollector) Load(item interface{}) error {
+	c.orders = append(c.orders, item.(*Order))
+	return nil
+}
+
+func (c *orderCollector) Flush() error {
+	return nil
+}
+
+func TestSkipOrderStreams(t *testing.T) {
+	genOrders := func(g *generator, start, count dssHuge) []*Order {
+		var orders []*Order
+		for i := start; i < start+count; i++ {
+			g.rowStart(TOrderLine)
+			orders = append(orders, g.makeOrder(i, 0))
+			g.rowStop(TOrderLine)
+		}
+		return orders
+	}
+
+	expected := genOrders(newGenerator(), 1, 20)
+
+	g := newGenerator()
+	g.sdOrder(TLine, 12)
+	g.sdLineItem(TLine, 12)
+	assert.Equal(t, expected[12:], genOrders(g, 13, 8))
+}
+
+func TestRefreshSet(t *testing.T) {
+	assert.Equal(t, int64(1500), RefreshRows())
+
+	c := &orderCollector{}
+	assert.NoError(t, DbGenRefresh(2, c))
+	assert.Len(t, c.orders, 1500)
+	// the new orders are sparse keys with seq 1, which never collide with the loaded orders
+	assert.Equal(t, int64(makeSparse(1501, 1)), int64(c.orders[0].OKey))
+	for _, line := range c.orders[0].Lines {
+		assert.Equal(t, c.orders[0].OKey, line.OKey)
+	}
+
+	keys := RefreshDeleteKeys(2)
+	assert.Len(t, keys, 1500)
+	assert.Equal(t, int64(makeSparse(1501, 0)), keys[0])
+}
+
+func TestFractionalScale(t *testing.T) {
+	defer func(defs []tDef) {
+		tDefs = defs
+	}(append([]tDef(nil), tDefs...))
+
+	scaleDownTDefs(0.01)
+	assert.Equal(t, dssHuge(2000), tDefs[TPart].base)
+	assert.Equal(t, dssHuge(100), tDefs[TSupp].base)
+	assert.Equal(t, dssHuge(1500), tDefs[TCust].base)
+	assert.Equal(t, dssHuge(15000), tDefs[TOrderLine].base)
+	assert.Equal(t, dssHuge(25), tDefs[TNation].base)
+	assert.Equal(t, int64(10), RefreshRows())
+}
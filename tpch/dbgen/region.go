@@ -42,12 +42,12 @@ func NewRegionLoader(w io.Writer) regionLoader {
 	return regionLoader{sink.NewCSVSinkWithDelimiter(w, '|')}
 }
 
-func makeRegion(idx dssHuge) *Region {
+func (g *generator) makeRegion(idx dssHuge) *Region {
 	region := &Region{}
 
 	region.Code = idx - 1
 	region.Text = regions.members[idx-1].text
 	region.Join = 0
-	region.Comment = makeText(rCmntLen, rCmntSd)
+	region.Comment = g.makeText(rCmntLen, rCmntSd)
 	return region
 }
@@ -68,21 +68,21 @@ func NewSuppLoader(w io.Writer) suppLoader {
 	return suppLoader{sink.NewCSVSinkWithDelimiter(w, '|')}
 }
 
-func makeSupp(idx dssHuge) *Supp {
+func (g *generator) makeSupp(idx dssHuge) *Supp {
 	supp := &Supp{}
 	supp.SuppKey = idx
 	supp.Name = fmt.Sprintf("Supplier#%09d", idx)
-	supp.Address = vStr(sAddrLen, sAddrSd)
-	i := random(0, dssHuge(nations.count-1), sNtrgSd)
+	supp.Address = g.vStr(sAddrLen, sAddrSd)
+	i := g.random(0, dssHuge(nations.count-1), sNtrgSd)
 	supp.NationCode = i
-	supp.Phone = genPhone(i, sPhneSd)
-	supp.Acctbal = random(sAbalMin, sAbalMax, sAbalSd)
-	supp.Comment = makeText(sCmntLen, sCmntSd)
+	supp.Phone = g.genPhone(i, sPhneSd)
+	supp.Acctbal = g.random(sAbalMin, sAbalMax, sAbalSd)
+	supp.Comment = g.makeText(sCmntLen, sCmntSd)
 
-	badPress := random(1, 10000, bbbCmntSd)
-	types := random(0, 100, bbbTypeSd)
-	noise := random(0, dssHuge(len(supp.Comment)-bbbCmntLen), bbbJnkSd)
-	offset := random(0, dssHuge(len(supp.Comment))-(bbbCmntLen+noise), bbbOffsetSd)
+	badPress := g.random(1, 10000, bbbCmntSd)
+	types := g.random(0, 100, bbbTypeSd)
+	noise := g.random(0, dssHuge(len(supp.Comment)-bbbCmntLen), bbbJnkSd)
+	offset := g.random(0, dssHuge(len(supp.Comment))-(bbbCmntLen+noise), bbbOffsetSd)
 
 	if badPress <= sCmntBbb {
 		if types < bbbDeadbeats {
@@ -105,14 +105,14 @@ func makeSupp(idx dssHuge) *Supp {
 	return supp
 }
 
-func sdSupp(child Table, skipCount dssHuge) {
-	advanceStream(sNtrgSd, skipCount, false)
-	advanceStream(sPhneSd, skipCount*3, false)
-	advanceStream(sAbalSd, skipCount, false)
-	advanceStream(sAddrSd, skipCount*9, false)
-	advanceStream(sCmntSd, skipCount*2, false)
-	advanceStream(bbbCmntSd, skipCount, false)
-	advanceStream(bbbJnkSd, skipCount, false)
-	advanceStream(bbbOffsetSd, skipCount, false)
-	advanceStream(bbbTypeSd, skipCount, false)
+func (g *generator) sdSupp(child Table, skipCount dssHuge) {
+	g.advanceStream(sNtrgSd, skipCount, false)
+	g.advanceStream(sPhneSd, skipCount*3, false)
+	g.advanceStream(sAbalSd, skipCount, false)
+	g.advanceStream(sAddrSd, skipCount*9, false)
+	g.advanceStream(sCmntSd, skipCount*2, false)
+	g.advanceStream(bbbCmntSd, skipCount, false)
+	g.advanceStream(bbbJnkSd, skipCount, false)
+	g.advanceStream(bbbOffsetSd, skipCount, false)
+	g.advanceStream(bbbTypeSd, skipCount, false)
 }
@@ -0,0 +1,43 @@
+package tpch
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPermutations(t *testing.T) {
+	assert.Len(t, queryPermutations, 41)
+	for i, perm := range queryPermutations {
+		sorted := append([]int(nil), perm...)
+		sort.Ints(sorted)
+		for q := 1; q <= 22; q++ {
+			assert.Equal(t, q, sorted[q-1], "stream %d is not a permutation of the 22 queries", i)
+		}
+	}
+	assert.Equal(t, "q14", streamQueries(0)[0])
+	assert.Equal(t, "q21", streamQueries(1)[0])
+}
+
+func TestMinStreams(t *testing.T) {
+	assert.Equal(t, 2, minStreams(1))
+	assert.Equal(t, 3, minStreams(10))
+	assert.Equal(t, 5, minStreams(100))
+	assert.Equal(t, 7, minStreams(1000))
+}
+
+func TestPowerSize(t *testing.T) {
+	timings := make([]time.Duration, 24)
+	for i := range timings {
+		timings[i] = 2 * time.Second
+	}
+	assert.InDelta(t, 1800, powerSize(timings, 1), 1e-6)
+
+	// timings shorter than 1/1000 of the longest one are raised to it
+	timings[0] = time.Nanosecond
+	timings[1] = 2000 * time.Second
+	expected := powerSize(append([]time.Duration{2 * time.Second}, timings[1:]...), 10)
+	assert.InDelta(t, expected, powerSize(timings, 10), 1e-6)
+}
@@ -1,7 +1,25 @@
 package tpch
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/go-tpc/tpch/dbgen"
+)
+
+// queries are the query templates, the substitution parameters are marked as :1, :2 and so on like qgen.
 var queries map[string]string
 
+// defaultParams are the substitution parameters used when qgen is disabled, the answers of check.go are based on them.
+var defaultParams map[string][]string
+
+// qgenQueries overrides the query templates when qgen is enabled. The mysql q15 reads the revenue0 view
+// created before the run with fixed parameters, so it's replaced by a CTE with the substitution parameter.
+var qgenQueries = map[string]string{
+	"q15:mysql": q15cte,
+}
+
 const (
 	q1 = `
 /*PLACEHOLDER*/ select
@@ -18,7 +36,7 @@ const (
 from
 	lineitem
 where
-	l_shipdate <= date_sub('1998-12-01', interval 108 day)
+	l_shipdate <= date_sub('1998-12-01', interval :1 day)
 group by
 	l_returnflag,
 	l_linestatus
@@ -45,11 +63,11 @@ from
 where
 	p_partkey = ps_partkey
 	and s_suppkey = ps_suppkey
-	and p_size = 30
-	and p_type like '%STEEL'
+	and p_size = :1
+	and p_type like '%:2'
 	and s_nationkey = n_nationkey
 	and n_regionkey = r_regionkey
-	and r_name = 'ASIA'
+	and r_name = ':3'
 	and ps_supplycost = (
 		select
 			min(ps_supplycost)
@@ -63,7 +81,7 @@ where
 			and s_suppkey = ps_suppkey
 			and s_nationkey = n_nationkey
 			and n_regionkey = r_regionkey
-			and r_name = 'ASIA'
+			and r_name = ':3'
 	)
 order by
 	s_acctbal desc,
@@ -83,11 +101,11 @@ from
 	orders,
 	lineitem
 where
-	c_mktsegment = 'AUTOMOBILE'
+	c_mktsegment = ':1'
 	and c_custkey = o_custkey
 	and l_orderkey = o_orderkey
-	and o_orderdate < '1995-03-13'
-	and l_shipdate > '1995-03-13'
+	and o_orderdate < ':2'
+	and l_shipdate > ':2'
 group by
 	l_orderkey,
 	o_orderdate,
@@ -104,8 +122,8 @@ limit 10;
 from
 	orders
 where
-	o_orderdate >= '1995-01-01'
-	and o_orderdate < date_add('1995-01-01', interval '3' month)
+	o_orderdate >= ':1'
+	and o_orderdate < date_add(':1', interval '3' month)
 	and exists (
 		select
 			*
@@ -138,9 +156,9 @@ where
 	and c_nationkey = s_nationkey
 	and s_nationkey = n_nationkey
 	and n_regionkey = r_regionkey
-	and r_name = 'MIDDLE EAST'
-	and o_orderdate >= '1994-01-01'
-	and o_orderdate < date_add('1994-01-01', interval '1' year)
+	and r_name = ':1'
+	and o_orderdate >= ':2'
+	and o_orderdate < date_add(':2', interval '1' year)
 group by
 	n_name
 order by
@@ -152,10 +170,10 @@ order by
 from
 	lineitem
 where
-	l_shipdate >= '1994-01-01'
-	and l_shipdate < date_add('1994-01-01', interval '1' year)
-	and l_discount between 0.06 - 0.01 and 0.06 + 0.01
-	and l_quantity < 24;
+	l_shipdate >= ':1'
+	and l_shipdate < date_add(':1', interval '1' year)
+	and l_discount between :2 - 0.01 and :2 + 0.01
+	and l_quantity < :3;
 `
 	q7 = `
 /*PLACEHOLDER*/ select
@@ -184,8 +202,8 @@ from
 			and s_nationkey = n1.n_nationkey
 			and c_nationkey = n2.n_nationkey
 			and (
-				(n1.n_name = 'JAPAN' and n2.n_name = 'INDIA')
-				or (n1.n_name = 'INDIA' and n2.n_name = 'JAPAN')
+				(n1.n_name = ':1' and n2.n_name = ':2')
+				or (n1.n_name = ':2' and n2.n_name = ':1')
 			)
 			and l_shipdate between '1995-01-01' and '1996-12-31'
 	) as shipping
@@ -202,7 +220,7 @@ order by
 /*PLACEHOLDER*/ select
 	o_year,
 	sum(case
-		when nation = 'INDIA' then volume
+		when nation = ':1' then volume
 		else 0
 	end) / sum(volume) as mkt_share
 from
@@ -227,10 +245,10 @@ from
 			and o_custkey = c_custkey
 			and c_nationkey = n1.n_nationkey
 			and n1.n_regionkey = r_regionkey
-			and r_name = 'ASIA'
+			and r_name = ':2'
 			and s_nationkey = n2.n_nationkey
 			and o_orderdate between '1995-01-01' and '1996-12-31'
-			and p_type = 'SMALL PLATED COPPER'
+			and p_type = ':3'
 	) as all_nations
 group by
 	o_year
@@ -262,7 +280,7 @@ from
 			and p_partkey = l_partkey
 			and o_orderkey = l_orderkey
 			and s_nationkey = n_nationkey
-			and p_name like '%dim%'
+			and p_name like '%:1%'
 	) as profit
 group by
 	nation,
@@ -289,8 +307,8 @@ from
 where
 	c_custkey = o_custkey
 	and l_orderkey = o_orderkey
-	and o_orderdate >= '1993-08-01'
-	and o_orderdate < date_add('1993-08-01', interval '3' month)
+	and o_orderdate >= ':1'
+	and o_orderdate < date_add(':1', interval '3' month)
 	and l_returnflag = 'R'
 	and c_nationkey = n_nationkey
 group by
@@ -316,12 +334,12 @@ from
 where
 	ps_suppkey = s_suppkey
 	and s_nationkey = n_nationkey
-	and n_name = 'MOZAMBIQUE'
+	and n_name = ':1'
 group by
 	ps_partkey having
 		sum(ps_supplycost * ps_availqty) > (
 			select
-				sum(ps_supplycost * ps_availqty) * 0.0001000000
+				sum(ps_supplycost * ps_availqty) * :2
 			from
 				partsupp,
 				supplier,
@@ -329,7 +347,7 @@ group by
 			where
 				ps_suppkey = s_suppkey
 				and s_nationkey = n_nationkey
-				and n_name = 'MOZAMBIQUE'
+				and n_name = ':1'
 		)
 order by
 	value desc;
@@ -354,11 +372,11 @@ from
 	lineitem
 where
 	o_orderkey = l_orderkey
-	and l_shipmode in ('RAIL', 'FOB')
+	and l_shipmode in (':1', ':2')
 	and l_commitdate < l_receiptdate
 	and l_shipdate < l_commitdate
-	and l_receiptdate >= '1997-01-01'
-	and l_receiptdate < date_add('1997-01-01', interval '1' year)
+	and l_receiptdate >= ':3'
+	and l_receiptdate < date_add(':3', interval '1' year)
 group by
 	l_shipmode
 order by
@@ -376,7 +394,7 @@ from
 		from
 			customer left outer join orders on
 				c_custkey = o_custkey
-				and o_comment not like '%pending%deposits%'
+				and o_comment not like '%:1%:2%'
 		group by
 			c_custkey
 	) c_orders
@@ -398,8 +416,8 @@ from
 	part
 where
 	l_partkey = p_partkey
-	and l_shipdate >= '1996-12-01'
-	and l_shipdate < date_add('1996-12-01', interval '1' month);
+	and l_shipdate >= ':1'
+	and l_shipdate < date_add(':1', interval '1' month);
 `
 	q15 = `
 /*PLACEHOLDER*/ select
@@ -433,9 +451,9 @@ from
 	part
 where
 	p_partkey = ps_partkey
-	and p_brand <> 'Brand#34'
-	and p_type not like 'LARGE BRUSHED%'
-	and p_size in (48, 19, 12, 4, 41, 7, 21, 39)
+	and p_brand <> ':1'
+	and p_type not like ':2%'
+	and p_size in (:3, :4, :5, :6, :7, :8, :9, :10)
 	and ps_suppkey not in (
 		select
 			s_suppkey
@@ -462,8 +480,8 @@ from
 	part
 where
 	p_partkey = l_partkey
-	and p_brand = 'Brand#44'
-	and p_container = 'WRAP PKG'
+	and p_brand = ':1'
+	and p_container = ':2'
 	and l_quantity < (
 		select
 			0.2 * avg(l_quantity)
@@ -493,7 +511,7 @@ where
 			lineitem
 		group by
 			l_orderkey having
-				sum(l_quantity) > 314
+				sum(l_quantity) > :1
 	)
 	and c_custkey = o_custkey
 	and o_orderkey = l_orderkey
@@ -517,9 +535,9 @@ from
 where
 	(
 		p_partkey = l_partkey
-		and p_brand = 'Brand#52'
+		and p_brand = ':1'
 		and p_container in ('SM CASE', 'SM BOX', 'SM PACK', 'SM PKG')
-		and l_quantity >= 4 and l_quantity <= 4 + 10
+		and l_quantity >= :4 and l_quantity <= :4 + 10
 		and p_size between 1 and 5
 		and l_shipmode in ('AIR', 'AIR REG')
 		and l_shipinstruct = 'DELIVER IN PERSON'
@@ -527,9 +545,9 @@ where
 	or
 	(
 		p_partkey = l_partkey
-		and p_brand = 'Brand#11'
+		and p_brand = ':2'
 		and p_container in ('MED BAG', 'MED BOX', 'MED PKG', 'MED PACK')
-		and l_quantity >= 18 and l_quantity <= 18 + 10
+		and l_quantity >= :5 and l_quantity <= :5 + 10
 		and p_size between 1 and 10
 		and l_shipmode in ('AIR', 'AIR REG')
 		and l_shipinstruct = 'DELIVER IN PERSON'
@@ -537,9 +555,9 @@ where
 	or
 	(
 		p_partkey = l_partkey
-		and p_brand = 'Brand#51'
+		and p_brand = ':3'
 		and p_container in ('LG CASE', 'LG BOX', 'LG PACK', 'LG PKG')
-		and l_quantity >= 29 and l_quantity <= 29 + 10
+		and l_quantity >= :6 and l_quantity <= :6 + 10
 		and p_size between 1 and 15
 		and l_shipmode in ('AIR', 'AIR REG')
 		and l_shipinstruct = 'DELIVER IN PERSON'
@@ -565,7 +583,7 @@ where
 				from
 					part
 				where
-					p_name like 'green%'
+					p_name like ':1%'
 			)
 			and ps_availqty > (
 				select
@@ -575,12 +593,12 @@ where
 				where
 					l_partkey = ps_partkey
 					and l_suppkey = ps_suppkey
-					and l_shipdate >= '1993-01-01'
-					and l_shipdate < date_add('1993-01-01', interval '1' year)
+					and l_shipdate >= ':2'
+					and l_shipdate < date_add(':2', interval '1' year)
 			)
 	)
 	and s_nationkey = n_nationkey
-	and n_name = 'ALGERIA'
+	and n_name = ':3'
 order by
 	s_name;
 `
@@ -618,7 +636,7 @@ where
 			and l3.l_receiptdate > l3.l_commitdate
 	)
 	and s_nationkey = n_nationkey
-	and n_name = 'EGYPT'
+	and n_name = ':1'
 group by
 	s_name
 order by
@@ -640,7 +658,7 @@ from
 			customer
 		where
 			substring(c_phone from 1 for 2) in
-				('20', '40', '22', '30', '39', '42', '21')
+				(':1', ':2', ':3', ':4', ':5', ':6', ':7')
 			and c_acctbal > (
 				select
 					avg(c_acctbal)
@@ -649,7 +667,7 @@ from
 				where
 					c_acctbal > 0.00
 					and substring(c_phone from 1 for 2) in
-						('20', '40', '22', '30', '39', '42', '21')
+						(':1', ':2', ':3', ':4', ':5', ':6', ':7')
 			)
 			and not exists (
 				select
@@ -667,6 +685,42 @@ order by
 `
 )
 
+const (
+	q15cte = `
+/*PLACEHOLDER*/ with revenue0 (supplier_no, total_revenue) as (
+	select
+		l_suppkey,
+		sum(l_extendedprice * (1 - l_discount))
+	from
+		lineitem
+	where
+		l_shipdate >= ':1'
+		and l_shipdate < date_add(':1', interval '3' month)
+	group by
+		l_suppkey
+)
+select
+	s_suppkey,
+	s_name,
+	s_address,
+	s_phone,
+	total_revenue
+from
+	supplier,
+	revenue0
+where
+	s_suppkey = supplier_no
+	and total_revenue = (
+		select
+			max(total_revenue)
+		from
+			revenue0
+	)
+order by
+	s_suppkey;
+`
+)
+
 const (
 	q1pg = `
 /*PLACEHOLDER*/ select
@@ -683,7 +737,7 @@ const (
 from
 	lineitem
 where
-	l_shipdate <= date '1998-12-01' - interval '1' day
+	l_shipdate <= date '1998-12-01' - interval ':1' day
 group by
 	l_returnflag,
 	l_linestatus
@@ -710,11 +764,11 @@ from
 where
 	p_partkey = ps_partkey
 	and s_suppkey = ps_suppkey
-	and p_size = 30
-	and p_type like '%STEEL'
+	and p_size = :1
+	and p_type like '%:2'
 	and s_nationkey = n_nationkey
 	and n_regionkey = r_regionkey
-	and r_name = 'ASIA'
+	and r_name = ':3'
 	and ps_supplycost = (
 			select
 					min(ps_supplycost)
@@ -728,7 +782,7 @@ where
 					and s_suppkey = ps_suppkey
 					and s_nationkey = n_nationkey
 					and n_regionkey = r_regionkey
-					and r_name = 'ASIA'
+					and r_name = ':3'
 	)
 order by
 	s_acctbal desc,
@@ -748,11 +802,11 @@ from
 	orders,
 	lineitem
 where
-	c_mktsegment = 'AUTOMOBILE'
+	c_mktsegment = ':1'
 	and c_custkey = o_custkey
 	and l_orderkey = o_orderkey
-	and o_orderdate < date '1995-03-13'
-	and l_shipdate > date '1995-03-13'
+	and o_orderdate < date ':2'
+	and l_shipdate > date ':2'
 group by
 	l_orderkey,
 	o_orderdate,
@@ -769,8 +823,8 @@ limit 10;
 from
 	orders
 where
-	o_orderdate >= date '1993-07-01'
-	and o_orderdate < date '1993-07-01' + interval '3' month
+	o_orderdate >= date ':1'
+	and o_orderdate < date ':1' + interval '3' month
 	and exists (
 		select
 			*
@@ -803,9 +857,9 @@ where
 	and c_nationkey = s_nationkey
 	and s_nationkey = n_nationkey
 	and n_regionkey = r_regionkey
-	and r_name = 'ASIA'
-	and o_orderdate >= date '1994-01-01'
-	and o_orderdate < date '1994-01-01' + interval '1' year
+	and r_name = ':1'
+	and o_orderdate >= date ':2'
+	and o_orderdate < date ':2' + interval '1' year
 group by
 	n_name
 order by
@@ -817,10 +871,10 @@ order by
 from
 	lineitem
 where
-	l_shipdate >= date '1994-01-01'
-	and l_shipdate < date '1994-01-01' + interval '1' year
-	and l_discount between 0.06 - 0.01 and 0.06 + 0.01
-	and l_quantity < 24;
+	l_shipdate >= date ':1'
+	and l_shipdate < date ':1' + interval '1' year
+	and l_discount between :2 - 0.01 and :2 + 0.01
+	and l_quantity < :3;
 `
 	q7pg = `
 /*PLACEHOLDER*/ select
@@ -849,8 +903,8 @@ from
 		and s_nationkey = n1.n_nationkey
 		and c_nationkey = n2.n_nationkey
 		and (
-			(n1.n_name = 'FRANCE' and n2.n_name = 'GERMANY')
-			or (n1.n_name = 'GERMANY' and n2.n_name = 'FRANCE')
+			(n1.n_name = ':1' and n2.n_name = ':2')
+			or (n1.n_name = ':2' and n2.n_name = ':1')
 		)
 		and l_shipdate between date('1995-01-01') and date('1996-12-31')
  ) as shipping
@@ -867,7 +921,7 @@ order by
 /*PLACEHOLDER*/ select
 	o_year,
 	sum(case
-	when nation = 'EGYPT' then volume
+	when nation = ':1' then volume
 	else 0
 	end) / sum(volume) as mkt_share
 from
@@ -892,10 +946,10 @@ from
 		and o_custkey = c_custkey
 		and c_nationkey = n1.n_nationkey
 		and n1.n_regionkey = r_regionkey
-		and r_name = 'AMERICA'
+		and r_name = ':2'
 		and s_nationkey = n2.n_nationkey
 		and o_orderdate between date '1995-01-01'  and date '1996-12-31'
-		and p_type = 'LARGE BRUSHED BRASS'
+		and p_type = ':3'
  ) as all_nations
 group by
 	o_year
@@ -927,7 +981,7 @@ from
 		and p_partkey = l_partkey
 		and o_orderkey = l_orderkey
 		and s_nationkey = n_nationkey
-		and p_name like '%green%'
+		and p_name like '%:1%'
  ) as profit
 group by
 	nation,
@@ -954,8 +1008,8 @@ from
 where
 	c_custkey = o_custkey
 	and l_orderkey = o_orderkey
-	and o_orderdate >= date('1993-10-01')
-	and o_orderdate < date('1993-10-01') + interval '3' month
+	and o_orderdate >= date(':1')
+	and o_orderdate < date(':1') + interval '3' month
 	and l_returnflag = 'R'
 	and c_nationkey = n_nationkey
 group by
@@ -981,13 +1035,13 @@ from
 where
 	ps_suppkey = s_suppkey
 	and s_nationkey = n_nationkey
-	and n_name = 'GERMANY'
+	and n_name = ':1'
 group by
 	ps_partkey
 having
 	sum(ps_supplycost * ps_availqty) > (
 		select
-			 sum(ps_supplycost * ps_availqty) * 0.0001 / (100 /* PUT THE SF HERE (size in GB) */)
+			 sum(ps_supplycost * ps_availqty) * :2
 		from
 			 partsupp,
 			 supplier,
@@ -995,7 +1049,7 @@ having
 		where
 			 ps_suppkey = s_suppkey
 			 and s_nationkey = n_nationkey
-			 and n_name = 'GERMANY'
+			 and n_name = ':1'
 	)
 order by
 	value desc;
@@ -1020,11 +1074,11 @@ from
 	lineitem
 where
 	o_orderkey = l_orderkey
-	and l_shipmode in ('MAIL', 'SHIP')
+	and l_shipmode in (':1', ':2')
 	and l_commitdate < l_receiptdate
 	and l_shipdate < l_commitdate
-	and l_receiptdate >= date('1994-01-01')
-	and l_receiptdate < date('1994-01-01') + interval '1' year
+	and l_receiptdate >= date(':3')
+	and l_receiptdate < date(':3') + interval '1' year
 group by
 	l_shipmode
 order by
@@ -1042,7 +1096,7 @@ from
 	from
 		 customer left outer join orders on
 			c_custkey = o_custkey
-			and o_comment not like '%special%requests%'
+			and o_comment not like '%:1%:2%'
 	group by
 		c_custkey
  ) as c_orders
@@ -1064,8 +1118,8 @@ from
 	part
 where
 	l_partkey = p_partkey
-	and l_shipdate >= date('1995-09-01')
-	and l_shipdate < date('1995-09-01') + interval '13' month;
+	and l_shipdate >= date(':1')
+	and l_shipdate < date(':1') + interval '1' month;
 `
 	q15pg = `
 /*PLACEHOLDER*/ with revenue (supplier_no, total_revenue) as (
@@ -1075,8 +1129,8 @@ where
 	from
 		lineitem
 	where
-		l_shipdate >= '1997-07-01'
-		and l_shipdate < date '1997-07-01' + interval '3' month
+		l_shipdate >= ':1'
+		and l_shipdate < date ':1' + interval '3' month
 	group by
 		l_suppkey
 )
@@ -1111,9 +1165,9 @@ from
 	part
 where
 	p_partkey = ps_partkey
-	and p_brand <> 'Brand#45'
-	and p_type not like 'MEDIUM POLISHED%'
-	and p_size in (49, 14, 23, 45, 19, 3, 36, 9)
+	and p_brand <> ':1'
+	and p_type not like ':2%'
+	and p_size in (:3, :4, :5, :6, :7, :8, :9, :10)
 	and ps_suppkey not in (
 		select
 			s_suppkey
@@ -1140,8 +1194,8 @@ from
 	part
 where
 	p_partkey = l_partkey
-	and p_brand = 'Brand#43'
-	and p_container = 'LG PACK'
+	and p_brand = ':1'
+	and p_container = ':2'
 	and l_quantity < (
 		select
 			0.2 * avg(l_quantity)
@@ -1171,7 +1225,7 @@ where
 			lineitem
 		group by
 			l_orderkey having
-				sum(l_quantity) > 300
+				sum(l_quantity) > :1
 	)
 	and c_custkey = o_custkey
 	and o_orderkey = l_orderkey
@@ -1195,9 +1249,9 @@ from
 where
 	(
 		p_partkey = l_partkey
-		and p_brand = 'Brand#31'
+		and p_brand = ':1'
 		and p_container in ('SM CASE', 'SM BOX', 'SM PACK', 'SM PKG')
-		and l_quantity >= 1 and l_quantity <= 1 + 10
+		and l_quantity >= :4 and l_quantity <= :4 + 10
 		and p_size between 1 and 5
 		and l_shipmode in ('AIR', 'AIR REG')
 		and l_shipinstruct = 'DELIVER IN PERSON'
@@ -1205,9 +1259,9 @@ where
 	or
 	(
 		p_partkey = l_partkey
-		and p_brand = 'Brand#32'
+		and p_brand = ':2'
 		and p_container in ('MED BAG', 'MED BOX', 'MED PKG', 'MED PACK')
-		and l_quantity >= 10 and l_quantity <= 10 + 10
+		and l_quantity >= :5 and l_quantity <= :5 + 10
 		and p_size between 1 and 10
 		and l_shipmode in ('AIR', 'AIR REG')
 		and l_shipinstruct = 'DELIVER IN PERSON'
@@ -1215,9 +1269,9 @@ where
 	or
 	(
 		p_partkey = l_partkey
-		and p_brand = 'Brand#33'
+		and p_brand = ':3'
 		and p_container in ('LG CASE', 'LG BOX', 'LG PACK', 'LG PKG')
-		and l_quantity >= 20 and l_quantity <= 20 + 10
+		and l_quantity >= :6 and l_quantity <= :6 + 10
 		and p_size between 1 and 15
 		and l_shipmode in ('AIR', 'AIR REG')
 		and l_shipinstruct = 'DELIVER IN PERSON'
@@ -1243,7 +1297,7 @@ where
 				from
 					part
 				where
-					p_name like 'forest%'
+					p_name like ':1%'
 			)
 			and ps_availqty > (
 				select
@@ -1253,12 +1307,12 @@ where
 				where
 					l_partkey = ps_partkey
 					and l_suppkey = ps_suppkey
-					and l_shipdate >= date('1994-01-01')
-					and l_shipdate < date('1994-01-01') + interval '1' year
+					and l_shipdate >= date(':2')
+					and l_shipdate < date(':2') + interval '1' year
 			)
 	)
 	and s_nationkey = n_nationkey
-	and n_name = 'CANADA'
+	and n_name = ':3'
 order by
 	s_name;
 `
@@ -1296,7 +1350,7 @@ where
 			and l3.l_receiptdate > l3.l_commitdate
 	)
 	and s_nationkey = n_nationkey
-	and n_name = 'SAUDI ARABIA'
+	and n_name = ':1'
 group by
 	s_name
 order by
@@ -1318,7 +1372,7 @@ from
 		 customer
 	 where
 		 substring(c_phone from 1 for 2) in
-			 ('13', '31', '23', '29', '30', '18', '17')
+			 (':1', ':2', ':3', ':4', ':5', ':6', ':7')
 		 and c_acctbal > (
 			 select
 				 avg(c_acctbal)
@@ -1327,7 +1381,7 @@ from
 			 where
 				 c_acctbal > 0.00
 				 and substring(c_phone from 1 for 2) in
-					 ('13', '31', '23', '29', '30', '18', '17')
+					 (':1', ':2', ':3', ':4', ':5', ':6', ':7')
 		 )
 		 and not exists (
 			 select
@@ -1392,8 +1446,75 @@ func init() {
 		"q21:postgres": q21pg,
 		"q22:postgres": q22pg,
 	}
+	defaultParams = map[string][]string{
+		"q1:mysql":     {"108"},
+		"q2:mysql":     {"30", "STEEL", "ASIA"},
+		"q3:mysql":     {"AUTOMOBILE", "1995-03-13"},
+		"q4:mysql":     {"1995-01-01"},
+		"q5:mysql":     {"MIDDLE EAST", "1994-01-01"},
+		"q6:mysql":     {"1994-01-01", "0.06", "24"},
+		"q7:mysql":     {"JAPAN", "INDIA"},
+		"q8:mysql":     {"INDIA", "ASIA", "SMALL PLATED COPPER"},
+		"q9:mysql":     {"dim"},
+		"q10:mysql":    {"1993-08-01"},
+		"q11:mysql":    {"MOZAMBIQUE", "0.0001000000"},
+		"q12:mysql":    {"RAIL", "FOB", "1997-01-01"},
+		"q13:mysql":    {"pending", "deposits"},
+		"q14:mysql":    {"1996-12-01"},
+		"q15:mysql":    {},
+		"q16:mysql":    {"Brand#34", "LARGE BRUSHED", "48", "19", "12", "4", "41", "7", "21", "39"},
+		"q17:mysql":    {"Brand#44", "WRAP PKG"},
+		"q18:mysql":    {"314"},
+		"q19:mysql":    {"Brand#52", "Brand#11", "Brand#51", "4", "18", "29"},
+		"q20:mysql":    {"green", "1993-01-01", "ALGERIA"},
+		"q21:mysql":    {"EGYPT"},
+		"q22:mysql":    {"20", "40", "22", "30", "39", "42", "21"},
+		"q1:postgres":  {"1"},
+		"q2:postgres":  {"30", "STEEL", "ASIA"},
+		"q3:postgres":  {"AUTOMOBILE", "1995-03-13"},
+		"q4:postgres":  {"1993-07-01"},
+		"q5:postgres":  {"ASIA", "1994-01-01"},
+		"q6:postgres":  {"1994-01-01", "0.06", "24"},
+		"q7:postgres":  {"FRANCE", "GERMANY"},
+		"q8:postgres":  {"EGYPT", "AMERICA", "LARGE BRUSHED BRASS"},
+		"q9:postgres":  {"green"},
+		"q10:postgres": {"1993-10-01"},
+		"q11:postgres": {"GERMANY", "0.0001 / (100 /* PUT THE SF HERE (size in GB) */)"},
+		"q12:postgres": {"MAIL", "SHIP", "1994-01-01"},
+		"q13:postgres": {"special", "requests"},
+		"q14:postgres": {"1995-09-01"},
+		"q15:postgres": {"1997-07-01"},
+		"q16:postgres": {"Brand#45", "MEDIUM POLISHED", "49", "14", "23", "45", "19", "3", "36", "9"},
+		"q17:postgres": {"Brand#43", "LG PACK"},
+		"q18:postgres": {"300"},
+		"q19:postgres": {"Brand#31", "Brand#32", "Brand#33", "1", "10", "20"},
+		"q20:postgres": {"forest", "1994-01-01", "CANADA"},
+		"q21:postgres": {"SAUDI ARABIA"},
+		"q22:postgres": {"13", "31", "23", "29", "30", "18", "17"},
+	}
 }
 
-func query(driver string, name string) string {
-	return queries[name+":"+driver]
+// query returns the query with the parameters generated by gen, or the default parameters if gen is nil.
+func query(driver string, name string, gen *dbgen.QGen) string {
+	key := name + ":" + driver
+	if gen == nil {
+		return substitute(queries[key], defaultParams[key])
+	}
+	template, ok := qgenQueries[key]
+	if !ok {
+		template = queries[key]
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(name, "q"))
+	if err != nil {
+		return template
+	}
+	return substitute(template, gen.Params(n))
+}
+
+// substitute replaces the parameter markers from the last one, so that :1 doesn't match the prefix of :10.
+func substitute(template string, params []string) string {
+	for i := len(params); i > 0; i-- {
+		template = strings.ReplaceAll(template, fmt.Sprintf(":%d", i), params[i-1])
+	}
+	return template
 }
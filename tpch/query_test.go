@@ -0,0 +1,24 @@
+package tpch
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/pingcap/go-tpc/tpch/dbgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuerySubstitution(t *testing.T) {
+	marker := regexp.MustCompile(`:\d`)
+	gen := dbgen.NewQGen(1, 1)
+	for _, driver := range []string{"mysql", "postgres"} {
+		for q := 1; q <= 22; q++ {
+			name := fmt.Sprintf("q%d", q)
+			assert.False(t, marker.MatchString(query(driver, name, nil)), "%s:%s", name, driver)
+			assert.False(t, marker.MatchString(query(driver, name, gen)), "%s:%s", name, driver)
+		}
+	}
+	assert.Contains(t, query("mysql", "q16", nil), "p_size in (48, 19, 12, 4, 41, 7, 21, 39)")
+	assert.Contains(t, query("mysql", "q15", gen), "with revenue0")
+}
@@ -0,0 +1,144 @@
+package tpch
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/workload"
+	"github.com/pingcap/go-tpc/tpch/dbgen"
+)
+
+const (
+	// refreshInsert and refreshDelete are the names of the refresh functions,
+	// they can be put in the query list like the other queries.
+	refreshInsert = "rf1"
+	refreshDelete = "rf2"
+
+	// refreshBatchSize is the number of orders inserted or deleted in one transaction.
+	refreshBatchSize = 100
+)
+
+func isRefresh(queryName string) bool {
+	return queryName == refreshInsert || queryName == refreshDelete
+}
+
+type orderCollector struct {
+	orders []*dbgen.Order
+}
+
+func (c *orderCollector) Load(item interface{}) error {
+	c.orders = append(c.orders, item.(*dbgen.Order))
+	return nil
+}
+
+func (c *orderCollector) Flush() error {
+	return nil
+}
+
+// runRefresh runs RF1 or RF2 with the next refresh set and returns the time taken,
+// the generation of the new orders is not measured.
+func (w *Workloader) runRefresh(ctx context.Context, s *tpchState, queryName string) (time.Duration, error) {
+	w.initDbGen.Do(func() {
+		dbgen.InitDbGen(w.cfg.ScaleFactor)
+	})
+
+	var (
+		set  int64
+		stmt []string
+	)
+	if queryName == refreshInsert {
+		set = atomic.AddInt64(&w.refreshInsertSet, 1)
+		c := &orderCollector{}
+		if err := dbgen.DbGenRefresh(set, c); err != nil {
+			return 0, fmt.Errorf("generate refresh set %d failed %v", set, err)
+		}
+		stmt = insertOrdersSQL(c.orders)
+	} else {
+		set = atomic.AddInt64(&w.refreshDeleteSet, 1)
+		stmt = deleteOrdersSQL(dbgen.RefreshDeleteKeys(set))
+	}
+
+	start := workload.StartTime(ctx)
+	err := execInTxns(ctx, s.Conn, stmt)
+	elapsed := time.Since(start)
+	w.measurement.Measure(queryName, elapsed, err)
+	if err != nil {
+		return 0, fmt.Errorf("execute %s with refresh set %d failed %w", queryName, set, err)
+	}
+	return elapsed, nil
+}
+
+// execInTxns executes every two statements in one transaction.
+func execInTxns(ctx context.Context, conn *sql.Conn, stmts []string) error {
+	for i := 0; i < len(stmts); i += 2 {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range stmts[i : i+2] {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertOrdersSQL returns the statements inserting the orders and then their lineitems of every batch.
+func insertOrdersSQL(orders []*dbgen.Order) []string {
+	var stmts []string
+	for i := 0; i < len(orders); i += refreshBatchSize {
+		batch := orders[i:min(i+refreshBatchSize, len(orders))]
+		var ordersBuf, linesBuf bytes.Buffer
+		ordersBuf.WriteString(`INSERT INTO orders (O_ORDERKEY, O_CUSTKEY, O_ORDERSTATUS, O_TOTALPRICE, O_ORDERDATE, O_ORDERPRIORITY, O_CLERK, O_SHIPPRIORITY, O_COMMENT) VALUES `)
+		linesBuf.WriteString(`INSERT INTO lineitem (L_ORDERKEY, L_PARTKEY, L_SUPPKEY, L_LINENUMBER, L_QUANTITY, L_EXTENDEDPRICE, L_DISCOUNT, L_TAX, L_RETURNFLAG, L_LINESTATUS, L_SHIPDATE, L_COMMITDATE, L_RECEIPTDATE, L_SHIPINSTRUCT, L_SHIPMODE, L_COMMENT) VALUES `)
+		for j, order := range batch {
+			if j > 0 {
+				ordersBuf.WriteString(",")
+				linesBuf.WriteString(",")
+			}
+			fmt.Fprintf(&ordersBuf, "(%d,%d,'%s',%s,'%s','%s','%s',%d,'%s')",
+				order.OKey, order.CustKey, order.Status, dbgen.FmtMoney(order.TotalPrice), order.Date,
+				order.OrderPriority, order.Clerk, order.ShipPriority, order.Comment)
+			for k, line := range order.Lines {
+				if k > 0 {
+					linesBuf.WriteString(",")
+				}
+				fmt.Fprintf(&linesBuf, "(%d,%d,%d,%d,%d,%s,%s,%s,'%s','%s','%s','%s','%s','%s','%s','%s')",
+					line.OKey, line.PartKey, line.SuppKey, line.LCnt, line.Quantity,
+					dbgen.FmtMoney(line.EPrice), dbgen.FmtMoney(line.Discount), dbgen.FmtMoney(line.Tax),
+					line.RFlag, line.LStatus, line.SDate, line.CDate, line.RDate,
+					line.ShipInstruct, line.ShipMode, line.Comment)
+			}
+		}
+		stmts = append(stmts, ordersBuf.String(), linesBuf.String())
+	}
+	return stmts
+}
+
+// deleteOrdersSQL returns the statements deleting the lineitems and then the orders of every batch.
+func deleteOrdersSQL(keys []int64) []string {
+	var stmts []string
+	for i := 0; i < len(keys); i += refreshBatchSize {
+		batch := keys[i:min(i+refreshBatchSize, len(keys))]
+		var in bytes.Buffer
+		for j, key := range batch {
+			if j > 0 {
+				in.WriteString(",")
+			}
+			fmt.Fprintf(&in, "%d", key)
+		}
+		stmts = append(stmts,
+			fmt.Sprintf("DELETE FROM lineitem WHERE L_ORDERKEY IN (%s)", in.String()),
+			fmt.Sprintf("DELETE FROM orders WHERE O_ORDERKEY IN (%s)", in.String()))
+	}
+	return stmts
+}
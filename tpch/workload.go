@@ -8,6 +8,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pingcap/go-tpc/pkg/measurement"
@@ -35,18 +36,32 @@ type Config struct {
 	DBName             string
 	RawQueries         string
 	QueryNames         []string
-	ScaleFactor        int
+	ScaleFactor        float64
 	EnableOutputCheck  bool
 	TiFlashReplica     int
 	AnalyzeTable       analyzeConfig
 	ExecExplainAnalyze bool
 	PrepareThreads     int
 
+	// Chunk and Chunks split the data generated by prepare like `dbgen -C Chunks -S Chunk`,
+	// so that several clients can prepare disjoint chunks concurrently.
+	Chunk  int
+	Chunks int
+
 	PlanReplayerConfig replayer.PlanReplayerConfig
 	EnablePlanReplayer bool
 
 	EnableQueryTuning bool
 
+	// RefreshStartSet is the first refresh set used by RF1 and RF2 in the run,
+	// a later run should start from the next set of the last run.
+	RefreshStartSet int64
+
+	// EnableQGen substitutes the query parameters generated by qgen for every query stream,
+	// the parameters of stream i are generated with the seed QGenSeed + i.
+	EnableQGen bool
+	QGenSeed   int64
+
 	// for prepare command only
 	OutputType string
 	OutputDir  string
@@ -58,6 +73,7 @@ type Config struct {
 type tpchState struct {
 	*workload.TpcState
 	queryIdx int
+	qgen     *dbgen.QGen
 }
 
 // Workloader is TPCH workload
@@ -68,20 +84,46 @@ type Workloader struct {
 	// stats
 	measurement *measurement.Measurement
 
+	// the threads generate their chunks after thread 0 created the tables,
+	// and thread 0 analyzes the tables after all the chunks are loaded
+	createTableWg sync.WaitGroup
+	loadWg        sync.WaitGroup
+	prepareErr    error
+
+	// the last refresh sets used by RF1 and RF2
+	initDbGen        sync.Once
+	refreshInsertSet int64
+	refreshDeleteSet int64
+
+	// the result of RunQphH
+	qphh *QphHResult
+
+	// the expected outputs of the queries checked by --check
+	answers map[string][][]string
+
 	PlanReplayerRunner *replayer.PlanReplayerRunner
+
+	// Comparer compares the results of the queries with another database if it's set.
+	Comparer *Comparer
 }
 
 // NewWorkloader new work loader
 func NewWorkloader(db *sql.DB, cfg *Config) workload.Workloader {
-	return &Workloader{
+	w := &Workloader{
 		db:  db,
 		cfg: cfg,
 		measurement: measurement.NewMeasurement(func(m *measurement.Measurement) {
 			m.MinLatency = 100 * time.Millisecond
 			m.MaxLatency = 20 * time.Minute
 			m.SigFigs = 3
-		}),
+		}, measurement.WithMetrics("tpch")),
+		refreshInsertSet: cfg.RefreshStartSet - 1,
+		refreshDeleteSet: cfg.RefreshStartSet - 1,
+		answers:          answerSets[answerKey{cfg.ScaleFactor, cfg.Driver}],
 	}
+	w.createTableWg.Add(cfg.PrepareThreads)
+	w.loadWg.Add(cfg.PrepareThreads)
+	return w
 }
 
 func (w *Workloader) getState(ctx context.Context) *tpchState {
@@ -105,6 +147,9 @@ func (w *Workloader) InitThread(ctx context.Context, threadID int) context.Conte
 		queryIdx: threadID % len(w.cfg.QueryNames),
 		TpcState: workload.NewTpcState(ctx, w.db),
 	}
+	if w.cfg.EnableQGen {
+		s.qgen = dbgen.NewQGen(w.cfg.QGenSeed+int64(threadID), w.cfg.ScaleFactor)
+	}
 	ctx = context.WithValue(ctx, stateKey, s)
 
 	return ctx
@@ -116,15 +161,37 @@ func (w *Workloader) CleanupThread(ctx context.Context, threadID int) {
 	s.Conn.Close()
 }
 
-// Prepare prepares data
+// Prepare prepares data, every thread generates a chunk of the tables concurrently.
 func (w *Workloader) Prepare(ctx context.Context, threadID int) error {
-	if threadID != 0 {
-		return nil
+	if threadID == 0 {
+		w.prepareErr = w.prepareTables(ctx)
+	}
+	w.createTableWg.Done()
+	w.createTableWg.Wait()
+	if w.prepareErr != nil {
+		return w.prepareErr
+	}
+
+	err := w.prepareChunk(ctx, threadID)
+	w.loadWg.Done()
+	if err != nil || threadID != 0 {
+		return err
 	}
+
+	// After data loaded, analyze tables to speed up queries.
+	w.loadWg.Wait()
+	if w.cfg.AnalyzeTable.Enable {
+		if err := w.analyzeTables(ctx, w.cfg.AnalyzeTable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Workloader) prepareTables(ctx context.Context) error {
 	if err := w.createTables(ctx); err != nil {
 		return err
 	}
-	var sqlLoader map[dbgen.Table]dbgen.Loader
 	if w.cfg.OutputType == "csv" {
 		if _, err := os.Stat(w.cfg.OutputDir); err != nil {
 			if os.IsNotExist(err) {
@@ -135,41 +202,65 @@ func (w *Workloader) Prepare(ctx context.Context, threadID int) error {
 				return err
 			}
 		}
+	}
+	dbgen.InitDbGen(w.cfg.ScaleFactor)
+	return nil
+}
+
+// prepareChunk generates the chunk of the thread, the threads split the chunk of this client
+// given by Chunk/Chunks, so the chunk of the thread i is (Chunk-1)*threads+i+1 of Chunks*threads.
+func (w *Workloader) prepareChunk(ctx context.Context, threadID int) error {
+	threads := w.cfg.PrepareThreads
+	chunk, chunks := (w.cfg.Chunk-1)*threads+threadID+1, w.cfg.Chunks*threads
+
+	var sqlLoader map[dbgen.Table]dbgen.Loader
+	if w.cfg.OutputType == "csv" {
+		fileName := func(table string) string {
+			if chunks == 1 {
+				return path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.%s.csv", w.DBName(), table))
+			}
+			return path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.%s.%d.csv", w.DBName(), table, chunk))
+		}
 		sqlLoader = map[dbgen.Table]dbgen.Loader{
-			dbgen.TOrder:  dbgen.NewOrderLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.orders.csv", w.DBName())))),
-			dbgen.TLine:   dbgen.NewLineItemLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.lineitem.csv", w.DBName())))),
-			dbgen.TPart:   dbgen.NewPartLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.part.csv", w.DBName())))),
-			dbgen.TPsupp:  dbgen.NewPartSuppLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.partsupp.csv", w.DBName())))),
-			dbgen.TSupp:   dbgen.NewSuppLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.supplier.csv", w.DBName())))),
-			dbgen.TCust:   dbgen.NewCustLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.customer.csv", w.DBName())))),
-			dbgen.TNation: dbgen.NewNationLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.nation.csv", w.DBName())))),
-			dbgen.TRegion: dbgen.NewRegionLoader(util.CreateFile(path.Join(w.cfg.OutputDir, fmt.Sprintf("%s.region.csv", w.DBName())))),
+			dbgen.TOrder: dbgen.NewOrderLoader(util.CreateFile(fileName("orders"))),
+			dbgen.TLine:  dbgen.NewLineItemLoader(util.CreateFile(fileName("lineitem"))),
+			dbgen.TPart:  dbgen.NewPartLoader(util.CreateFile(fileName("part"))),
+			dbgen.TPsupp: dbgen.NewPartSuppLoader(util.CreateFile(fileName("partsupp"))),
+			dbgen.TSupp:  dbgen.NewSuppLoader(util.CreateFile(fileName("supplier"))),
+			dbgen.TCust:  dbgen.NewCustLoader(util.CreateFile(fileName("customer"))),
+		}
+		if generatesFixedTables(chunk) {
+			sqlLoader[dbgen.TNation] = dbgen.NewNationLoader(util.CreateFile(fileName("nation")))
+			sqlLoader[dbgen.TRegion] = dbgen.NewRegionLoader(util.CreateFile(fileName("region")))
 		}
 	} else {
+		// the threads load their chunks concurrently, so every loader inserts with one connection
+		const concurrency = 1
 		sqlLoader = map[dbgen.Table]dbgen.Loader{
-			dbgen.TOrder:  NewOrderLoader(ctx, w.db, w.cfg.PrepareThreads),
-			dbgen.TLine:   NewLineItemLoader(ctx, w.db, w.cfg.PrepareThreads),
-			dbgen.TPart:   NewPartLoader(ctx, w.db, w.cfg.PrepareThreads),
-			dbgen.TPsupp:  NewPartSuppLoader(ctx, w.db, w.cfg.PrepareThreads),
-			dbgen.TSupp:   NewSuppLoader(ctx, w.db, w.cfg.PrepareThreads),
-			dbgen.TCust:   NewCustLoader(ctx, w.db, w.cfg.PrepareThreads),
-			dbgen.TNation: NewNationLoader(ctx, w.db, w.cfg.PrepareThreads),
-			dbgen.TRegion: NewRegionLoader(ctx, w.db, w.cfg.PrepareThreads),
+			dbgen.TOrder: NewOrderLoader(ctx, w.db, concurrency),
+			dbgen.TLine:  NewLineItemLoader(ctx, w.db, concurrency),
+			dbgen.TPart:  NewPartLoader(ctx, w.db, concurrency),
+			dbgen.TPsupp: NewPartSuppLoader(ctx, w.db, concurrency),
+			dbgen.TSupp:  NewSuppLoader(ctx, w.db, concurrency),
+			dbgen.TCust:  NewCustLoader(ctx, w.db, concurrency),
+		}
+		if generatesFixedTables(chunk) {
+			sqlLoader[dbgen.TNation] = NewNationLoader(ctx, w.db, concurrency)
+			sqlLoader[dbgen.TRegion] = NewRegionLoader(ctx, w.db, concurrency)
 		}
 	}
 
-	dbgen.InitDbGen(int64(w.cfg.ScaleFactor))
-	if err := dbgen.DbGen(sqlLoader, []dbgen.Table{dbgen.TNation, dbgen.TRegion, dbgen.TCust, dbgen.TSupp, dbgen.TPartPsupp, dbgen.TOrderLine}); err != nil {
-		return err
+	tables := []dbgen.Table{dbgen.TCust, dbgen.TSupp, dbgen.TPartPsupp, dbgen.TOrderLine}
+	if generatesFixedTables(chunk) {
+		tables = append([]dbgen.Table{dbgen.TNation, dbgen.TRegion}, tables...)
 	}
+	return dbgen.DbGenChunk(sqlLoader, tables, chunk, chunks)
+}
 
-	// After data loaded, analyze tables to speed up queries.
-	if w.cfg.AnalyzeTable.Enable {
-		if err := w.analyzeTables(ctx, w.cfg.AnalyzeTable); err != nil {
-			return err
-		}
-	}
-	return nil
+// generatesFixedTables returns whether the chunk generates nation and region, which do not scale
+// and are only generated by the first chunk, so the other chunks create no files for them.
+func generatesFixedTables(chunk int) bool {
+	return chunk == 1
 }
 
 func (w *Workloader) analyzeTables(ctx context.Context, acfg analyzeConfig) error {
@@ -210,7 +301,16 @@ func (w *Workloader) Run(ctx context.Context, threadID int) error {
 	}
 
 	queryName := w.cfg.QueryNames[s.queryIdx%len(w.cfg.QueryNames)]
-	query := query(w.cfg.Driver, queryName)
+	_, err := w.runQuery(ctx, s, queryName)
+	return err
+}
+
+// runQuery runs the query or refresh function and returns the time taken until all the results are fetched.
+func (w *Workloader) runQuery(ctx context.Context, s *tpchState, queryName string) (time.Duration, error) {
+	if isRefresh(queryName) {
+		return w.runRefresh(ctx, s, queryName)
+	}
+	query := query(w.cfg.Driver, queryName, s.qgen)
 	// only for driver == mysql and EnablePlanReplayer == true
 	if w.cfg.EnablePlanReplayer && w.cfg.Driver == "mysql" {
 		w.dumpPlanReplayer(ctx, s, query, queryName)
@@ -219,26 +319,34 @@ func (w *Workloader) Run(ctx context.Context, threadID int) error {
 	if w.cfg.ExecExplainAnalyze {
 		query = strings.Replace(query, "/*PLACEHOLDER*/", "explain analyze", 1)
 	}
-	start := time.Now()
+	start := workload.StartTime(ctx)
 	rows, err := s.Conn.QueryContext(ctx, query)
 	defer w.measurement.Measure(queryName, time.Now().Sub(start), err)
 	if err != nil {
-		return fmt.Errorf("execute %s failed %v", queryName, err)
+		return 0, fmt.Errorf("execute %s failed %v", queryName, err)
 	}
 	defer rows.Close()
 
 	if w.cfg.ExecExplainAnalyze {
 		table, err := util.RenderExplainAnalyze(rows)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		util.StdErrLogger.Printf("explain analyze result of query %s (takes %s):\n%s\n", queryName, time.Now().Sub(start), table)
-		return nil
+		return time.Since(start), nil
 	}
-	if err := w.scanQueryResult(queryName, rows); err != nil {
-		return fmt.Errorf("check %s failed %v", queryName, err)
+	cols, got, err := ScanRows(rows)
+	if err != nil {
+		return 0, fmt.Errorf("scan %s failed %v", queryName, err)
 	}
-	return nil
+	elapsed := time.Since(start)
+	if err := w.checkQueryResult(queryName, cols, got); err != nil {
+		return 0, fmt.Errorf("check %s failed %v", queryName, err)
+	}
+	if w.Comparer != nil {
+		w.Comparer.compare(ctx, queryName, query, queryColPrecisions[queryName], cols, got)
+	}
+	return elapsed, nil
 }
 
 // Cleanup cleans up workloader
@@ -282,6 +390,31 @@ func outputRtMeasurement(outputStyle string, prefix string, opMeasurement map[st
 
 func (w *Workloader) OutputStats(ifSummaryReport bool) {
 	w.measurement.Output(ifSummaryReport, w.cfg.OutputStyle, outputRtMeasurement)
+	if ifSummaryReport && w.qphh != nil {
+		w.outputQphH()
+	}
+	if ifSummaryReport && w.Comparer != nil {
+		w.Comparer.OutputStats(w.cfg.OutputStyle)
+	}
+}
+
+// Results implements workload.ResultProvider, it returns Power@Size, Throughput@Size
+// and QphH@Size, or nil if RunQphH is not run.
+func (w *Workloader) Results() map[string]float64 {
+	if w.qphh == nil {
+		return nil
+	}
+	return map[string]float64{
+		"streams":        float64(w.qphh.Streams),
+		"powerSize":      w.qphh.PowerSize,
+		"throughputSize": w.qphh.ThroughputSize,
+		"qphhSize":       w.qphh.QphHSize,
+	}
+}
+
+// Measurement returns the response time measurement of the workload.
+func (w *Workloader) Measurement() *measurement.Measurement {
+	return w.measurement
 }
 
 // DBName returns the name of test db.
@@ -320,5 +453,8 @@ func (w *Workloader) Exec(sql string) error {
 	if err != nil {
 		return err
 	}
+	if w.Comparer != nil {
+		return w.Comparer.Exec(sql)
+	}
 	return nil
 }
@@ -0,0 +1,44 @@
+package tpch
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/pingcap/go-tpc/tpch/dbgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareChunkCSV(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWorkloader(nil, &Config{
+		DBName:         "test",
+		ScaleFactor:    0.001,
+		PrepareThreads: 2,
+		Chunk:          1,
+		Chunks:         1,
+		OutputType:     "csv",
+		OutputDir:      dir,
+	}).(*Workloader)
+	dbgen.InitDbGen(w.cfg.ScaleFactor)
+	for threadID := 0; threadID < 2; threadID++ {
+		assert.NoError(t, w.prepareChunk(context.Background(), threadID))
+	}
+
+	// only the first chunk generates nation and region
+	for _, table := range []string{"nation", "region", "customer", "lineitem"} {
+		info, err := os.Stat(path.Join(dir, "test."+table+".1.csv"))
+		assert.NoError(t, err)
+		assert.NotZero(t, info.Size(), table)
+	}
+	for _, table := range []string{"nation", "region"} {
+		_, err := os.Stat(path.Join(dir, "test."+table+".2.csv"))
+		assert.True(t, os.IsNotExist(err), table)
+	}
+	for _, table := range []string{"customer", "lineitem"} {
+		info, err := os.Stat(path.Join(dir, "test."+table+".2.csv"))
+		assert.NoError(t, err)
+		assert.NotZero(t, info.Size(), table)
+	}
+}
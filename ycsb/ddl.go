@@ -0,0 +1,34 @@
+package ycsb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const tableName = "usertable"
+
+func fieldName(i int) string {
+	return fmt.Sprintf("FIELD%d", i)
+}
+
+// createTable creates the usertable with the key and the string fields of YCSB.
+func (w *Workloader) createTable(ctx context.Context) error {
+	s := getState(ctx)
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "CREATE TABLE IF NOT EXISTS %s (\n    YCSB_KEY VARCHAR(64) NOT NULL,\n", tableName)
+	for i := 0; i < w.cfg.FieldCount; i++ {
+		fmt.Fprintf(&buf, "    %s VARCHAR(%d),\n", fieldName(i), w.cfg.FieldLength)
+	}
+	buf.WriteString("    PRIMARY KEY (YCSB_KEY)\n)")
+	fmt.Printf("creating table %s\n", tableName)
+	_, err := s.Conn.ExecContext(ctx, buf.String())
+	return err
+}
+
+func (w *Workloader) dropTable(ctx context.Context) error {
+	s := getState(ctx)
+	fmt.Printf("DROP TABLE IF EXISTS %s\n", tableName)
+	_, err := s.Conn.ExecContext(ctx, "DROP TABLE IF EXISTS "+tableName)
+	return err
+}
@@ -0,0 +1,163 @@
+package ycsb
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	distUniform = "uniform"
+	distZipfian = "zipfian"
+	distLatest  = "latest"
+	distHotspot = "hotspot"
+
+	// zipfianConstant is the skew of the zipfian distributions of YCSB.
+	zipfianConstant = 0.99
+	// scrambledItems and scrambledZetan are the item count and its precomputed zeta of the
+	// scrambled zipfian distribution of YCSB, whose keys are hashed into the record count.
+	scrambledItems = 10000000000
+	scrambledZetan = 26.46902820178302
+)
+
+// keyChooser chooses the key number of an operation among the count records inserted.
+type keyChooser interface {
+	next(r *rand.Rand, count int64) int64
+}
+
+func newKeyChooser(cfg *Config, zetan float64) (keyChooser, error) {
+	switch cfg.RequestDistribution {
+	case distUniform:
+		return uniformChooser{}, nil
+	case distZipfian:
+		return &scrambledZipfianChooser{zipf: newZipfian(scrambledItems, zipfianConstant, scrambledZetan)}, nil
+	case distLatest:
+		return &latestChooser{zipf: newZipfian(cfg.RecordCount, zipfianConstant, zetan)}, nil
+	case distHotspot:
+		if cfg.HotspotDataFraction < 0 || cfg.HotspotDataFraction > 1 || cfg.HotspotOpnFraction < 0 || cfg.HotspotOpnFraction > 1 {
+			return nil, fmt.Errorf("hotspot fractions must be in [0, 1], got data %v, operation %v", cfg.HotspotDataFraction, cfg.HotspotOpnFraction)
+		}
+		return hotspotChooser{dataFraction: cfg.HotspotDataFraction, opnFraction: cfg.HotspotOpnFraction}, nil
+	default:
+		return nil, fmt.Errorf("unknown request distribution %s, valid values are uniform, zipfian, latest and hotspot", cfg.RequestDistribution)
+	}
+}
+
+type uniformChooser struct{}
+
+func (uniformChooser) next(r *rand.Rand, count int64) int64 {
+	return r.Int63n(count)
+}
+
+// scrambledZipfianChooser spreads the popular items of a zipfian distribution over the key space,
+// so the hot keys are not clustered at the beginning of the table.
+type scrambledZipfianChooser struct {
+	zipf *zipfian
+}
+
+func (c *scrambledZipfianChooser) next(r *rand.Rand, count int64) int64 {
+	return int64(fnvHash64(uint64(c.zipf.next(r, scrambledItems))) % uint64(count))
+}
+
+// latestChooser prefers the recently inserted keys.
+type latestChooser struct {
+	zipf *zipfian
+}
+
+func (c *latestChooser) next(r *rand.Rand, count int64) int64 {
+	return count - 1 - c.zipf.next(r, count)
+}
+
+// hotspotChooser chooses the keys in the first dataFraction of the key space for
+// opnFraction of the operations.
+type hotspotChooser struct {
+	dataFraction float64
+	opnFraction  float64
+}
+
+func (c hotspotChooser) next(r *rand.Rand, count int64) int64 {
+	hot := int64(float64(count) * c.dataFraction)
+	if hot == 0 || hot == count {
+		return r.Int63n(count)
+	}
+	if r.Float64() < c.opnFraction {
+		return r.Int63n(hot)
+	}
+	return hot + r.Int63n(count-hot)
+}
+
+// zipfian generates the items in [0, items) of the zipfian distribution by the algorithm of
+// "Quickly Generating Billion-Record Synthetic Databases", Jim Gray et al, SIGMOD 1994, like YCSB.
+// The zeta of the items is extended incrementally when the item count grows.
+type zipfian struct {
+	items int64
+	theta float64
+	zetan float64
+	zeta2 float64
+	alpha float64
+	eta   float64
+}
+
+func newZipfian(items int64, theta float64, zetan float64) *zipfian {
+	z := &zipfian{
+		items: items,
+		theta: theta,
+		zetan: zetan,
+		zeta2: zeta(0, 2, theta, 0),
+		alpha: 1 / (1 - theta),
+	}
+	z.eta = z.computeEta()
+	return z
+}
+
+func (z *zipfian) computeEta() float64 {
+	return (1 - math.Pow(2/float64(z.items), 1-z.theta)) / (1 - z.zeta2/z.zetan)
+}
+
+func (z *zipfian) next(r *rand.Rand, items int64) int64 {
+	if items > z.items {
+		z.zetan = zeta(z.items, items, z.theta, z.zetan)
+		z.items = items
+		z.eta = z.computeEta()
+	}
+
+	u := r.Float64()
+	uz := u * z.zetan
+	if uz < 1 {
+		return 0
+	}
+	if uz < 1+math.Pow(0.5, z.theta) {
+		return 1
+	}
+	item := int64(float64(z.items) * math.Pow(z.eta*u-z.eta+1, z.alpha))
+	if item >= z.items {
+		item = z.items - 1
+	}
+	return item
+}
+
+// zeta extends the zeta of the first st items, initialZeta, to n items.
+func zeta(st int64, n int64, theta float64, initialZeta float64) float64 {
+	sum := initialZeta
+	for i := st; i < n; i++ {
+		sum += 1 / math.Pow(float64(i+1), theta)
+	}
+	return sum
+}
+
+// fnvHash64 is the 64-bit FNV-1a hash of the 8 bytes of the value, used by YCSB to scramble
+// the key numbers.
+func fnvHash64(val uint64) uint64 {
+	const (
+		offsetBasis = 0xCBF29CE484222325
+		prime       = 1099511628211
+	)
+	hash := uint64(offsetBasis)
+	for i := 0; i < 8; i++ {
+		octet := val & 0xff
+		val >>= 8
+		hash ^= octet
+		hash *= prime
+	}
+	return hash
+}
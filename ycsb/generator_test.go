@@ -0,0 +1,87 @@
+package ycsb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFnvHash64(t *testing.T) {
+	// the FNV-1a hash of 8 zero bytes
+	assert.Equal(t, uint64(0xa8c7f832281a39c5), fnvHash64(0))
+	assert.NotEqual(t, fnvHash64(1), fnvHash64(2))
+	assert.Equal(t, "user"+"12161962213042174405", buildKey(0))
+}
+
+func TestZipfian(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	z := newZipfian(1000, zipfianConstant, zeta(0, 1000, zipfianConstant, 0))
+	counts := make([]int, 1000)
+	for i := 0; i < 100000; i++ {
+		item := z.next(r, 1000)
+		assert.True(t, item >= 0 && item < 1000)
+		counts[item]++
+	}
+	// the first items are the most popular ones
+	assert.Greater(t, counts[0], counts[1])
+	assert.Greater(t, counts[1], counts[10])
+	assert.Greater(t, counts[10], counts[500])
+
+	// the zeta is extended incrementally
+	for i := 0; i < 1000; i++ {
+		item := z.next(r, 2000)
+		assert.True(t, item >= 0 && item < 2000)
+	}
+	assert.InDelta(t, zeta(0, 2000, zipfianConstant, 0), z.zetan, 1e-9)
+}
+
+func TestKeyChoosers(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const count = 1000
+
+	cfg := &Config{RecordCount: count, HotspotDataFraction: 0.2, HotspotOpnFraction: 0.8}
+	for _, dist := range []string{distUniform, distZipfian, distLatest, distHotspot} {
+		cfg.RequestDistribution = dist
+		chooser, err := newKeyChooser(cfg, zeta(0, count, zipfianConstant, 0))
+		assert.NoError(t, err)
+		for i := 0; i < 10000; i++ {
+			key := chooser.next(r, count)
+			assert.True(t, key >= 0 && key < count, "%s chooses %d", dist, key)
+		}
+	}
+
+	cfg.RequestDistribution = distLatest
+	chooser, _ := newKeyChooser(cfg, zeta(0, count, zipfianConstant, 0))
+	latest := 0
+	for i := 0; i < 10000; i++ {
+		if chooser.next(r, count) >= count-10 {
+			latest++
+		}
+	}
+	assert.Greater(t, latest, 3000)
+
+	cfg.RequestDistribution = distHotspot
+	chooser, _ = newKeyChooser(cfg, 0)
+	hot := 0
+	for i := 0; i < 10000; i++ {
+		if chooser.next(r, count) < count/5 {
+			hot++
+		}
+	}
+	assert.InDelta(t, 8000, hot, 300)
+
+	cfg.RequestDistribution = "exponential"
+	_, err := newKeyChooser(cfg, 0)
+	assert.Error(t, err)
+	cfg.RequestDistribution = distHotspot
+	cfg.HotspotOpnFraction = 1.5
+	_, err = newKeyChooser(cfg, 0)
+	assert.Error(t, err)
+}
+
+func TestConvertToPQ(t *testing.T) {
+	query := "UPDATE usertable SET FIELD0 = ? WHERE YCSB_KEY = ?"
+	assert.Equal(t, query, convertToPQ(query, "mysql"))
+	assert.Equal(t, "UPDATE usertable SET FIELD0 = $1 WHERE YCSB_KEY = $2", convertToPQ(query, "postgres"))
+}
@@ -0,0 +1,532 @@
+package ycsb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/go-tpc/pkg/measurement"
+	"github.com/pingcap/go-tpc/pkg/sink"
+	"github.com/pingcap/go-tpc/pkg/util"
+	"github.com/pingcap/go-tpc/pkg/workload"
+)
+
+type contextKey string
+
+const stateKey = contextKey("ycsb")
+
+const (
+	opRead            = "read"
+	opUpdate          = "update"
+	opInsert          = "insert"
+	opScan            = "scan"
+	opReadModifyWrite = "read_modify_write"
+)
+
+// coreWorkload is the operation mix and the default request distribution of a YCSB core workload.
+type coreWorkload struct {
+	read, update, insert, scan, readModifyWrite float64
+	distribution                                string
+}
+
+var coreWorkloads = map[string]coreWorkload{
+	// update heavy
+	"a": {read: 0.5, update: 0.5, distribution: distZipfian},
+	// read mostly
+	"b": {read: 0.95, update: 0.05, distribution: distZipfian},
+	// read only
+	"c": {read: 1, distribution: distZipfian},
+	// read latest
+	"d": {read: 0.95, insert: 0.05, distribution: distLatest},
+	// short ranges
+	"e": {scan: 0.95, insert: 0.05, distribution: distZipfian},
+	// read-modify-write
+	"f": {read: 0.5, readModifyWrite: 0.5, distribution: distZipfian},
+}
+
+// Config is the configuration for ycsb workload
+type Config struct {
+	Driver  string
+	DBName  string
+	Threads int
+
+	// Workload is one of the core workloads a, b, c, d, e and f.
+	Workload    string
+	RecordCount int64
+	FieldCount  int
+	FieldLength int
+
+	// RequestDistribution is one of uniform, zipfian, latest and hotspot, empty means the
+	// distribution of the workload.
+	RequestDistribution string
+	HotspotDataFraction float64
+	HotspotOpnFraction  float64
+	MaxScanLength       int
+
+	MaxMeasureLatency time.Duration
+
+	// output style
+	OutputStyle string
+}
+
+type operation struct {
+	name       string
+	proportion float64
+	action     func(ctx context.Context, s *ycsbState) error
+}
+
+type ycsbState struct {
+	*workload.TpcState
+	chooser keyChooser
+
+	readStmt    *sql.Stmt
+	scanStmt    *sql.Stmt
+	insertStmt  *sql.Stmt
+	updateStmts []*sql.Stmt
+
+	values []sql.RawBytes
+	dest   []interface{}
+}
+
+// Workloader is the YCSB core workload on the usertable
+type Workloader struct {
+	db  *sql.DB
+	cfg *Config
+
+	ops   []operation
+	zetan float64
+
+	// insertSeq is the next key number to insert, and insertCount is the number of records
+	// inserted, which bounds the keys chosen by the other operations.
+	insertSeq   int64
+	insertCount int64
+
+	createTableWg sync.WaitGroup
+
+	// stats
+	measurement *measurement.Measurement
+}
+
+// NewWorkloader creates the ycsb workloader
+func NewWorkloader(db *sql.DB, cfg *Config) (workload.Workloader, error) {
+	core, ok := coreWorkloads[strings.ToLower(cfg.Workload)]
+	if !ok {
+		return nil, fmt.Errorf("unknown workload %s, valid values are a, b, c, d, e and f", cfg.Workload)
+	}
+	if cfg.RecordCount <= 0 || cfg.FieldCount <= 0 || cfg.FieldLength <= 0 {
+		return nil, fmt.Errorf("record count, field count and field length must be positive")
+	}
+	if core.scan > 0 && cfg.MaxScanLength <= 0 {
+		return nil, fmt.Errorf("max scan length must be positive")
+	}
+	if len(cfg.RequestDistribution) == 0 {
+		cfg.RequestDistribution = core.distribution
+	}
+
+	w := &Workloader{
+		db:          db,
+		cfg:         cfg,
+		insertSeq:   cfg.RecordCount,
+		insertCount: cfg.RecordCount,
+		measurement: measurement.NewMeasurement(func(m *measurement.Measurement) {
+			m.MaxLatency = cfg.MaxMeasureLatency
+		}, measurement.WithMetrics("ycsb")),
+	}
+	if cfg.RequestDistribution == distLatest {
+		w.zetan = zeta(0, cfg.RecordCount, zipfianConstant, 0)
+	}
+	if _, err := newKeyChooser(cfg, w.zetan); err != nil {
+		return nil, err
+	}
+
+	for _, op := range []operation{
+		{name: opRead, proportion: core.read, action: w.runRead},
+		{name: opUpdate, proportion: core.update, action: w.runUpdate},
+		{name: opInsert, proportion: core.insert, action: w.runInsert},
+		{name: opScan, proportion: core.scan, action: w.runScan},
+		{name: opReadModifyWrite, proportion: core.readModifyWrite, action: w.runReadModifyWrite},
+	} {
+		if op.proportion > 0 {
+			w.ops = append(w.ops, op)
+		}
+	}
+
+	w.createTableWg.Add(cfg.Threads)
+	return w, nil
+}
+
+func getState(ctx context.Context) *ycsbState {
+	s := ctx.Value(stateKey).(*ycsbState)
+	return s
+}
+
+// Name implements Workloader interface
+func (w *Workloader) Name() string {
+	return "ycsb"
+}
+
+// InitThread implements Workloader interface
+func (w *Workloader) InitThread(ctx context.Context, threadID int) context.Context {
+	// the distribution is checked by NewWorkloader
+	chooser, _ := newKeyChooser(w.cfg, w.zetan)
+	s := &ycsbState{
+		TpcState: workload.NewTpcState(ctx, w.db),
+		chooser:  chooser,
+		values:   make([]sql.RawBytes, w.cfg.FieldCount+1),
+		dest:     make([]interface{}, w.cfg.FieldCount+1),
+	}
+	for i := range s.values {
+		s.dest[i] = &s.values[i]
+	}
+	return context.WithValue(ctx, stateKey, s)
+}
+
+// CleanupThread implements Workloader interface
+func (w *Workloader) CleanupThread(ctx context.Context, threadID int) {
+	s := getState(ctx)
+	s.closeStmts()
+	if s.Conn != nil {
+		s.Conn.Close()
+	}
+}
+
+// Prepare creates the usertable, then every thread loads its range of the records.
+func (w *Workloader) Prepare(ctx context.Context, threadID int) error {
+	if threadID == 0 {
+		if err := w.createTable(ctx); err != nil {
+			return err
+		}
+	}
+	w.createTableWg.Done()
+	w.createTableWg.Wait()
+
+	start := w.cfg.RecordCount * int64(threadID) / int64(w.cfg.Threads)
+	end := w.cfg.RecordCount * int64(threadID+1) / int64(w.cfg.Threads)
+	return w.loadRecords(ctx, start, end)
+}
+
+func (w *Workloader) loadRecords(ctx context.Context, start, end int64) error {
+	fmt.Printf("load to %s [%d, %d)\n", tableName, start, end)
+	s := getState(ctx)
+	l := sink.NewSQLSink(w.db, fmt.Sprintf("INSERT INTO %s VALUES ", tableName), 3, time.Second)
+	row := make([]interface{}, w.cfg.FieldCount+1)
+	for keyNum := start; keyNum < end; keyNum++ {
+		row[0] = buildKey(keyNum)
+		for i := 1; i < len(row); i++ {
+			row[i] = randValue(s.R, w.cfg.FieldLength)
+		}
+		if err := l.WriteRow(ctx, row...); err != nil {
+			return err
+		}
+	}
+	return l.Flush(ctx)
+}
+
+// CheckPrepare checks that all the records are loaded
+func (w *Workloader) CheckPrepare(ctx context.Context, threadID int) error {
+	if threadID != 0 {
+		return nil
+	}
+	s := getState(ctx)
+	var count int64
+	if err := s.Conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+tableName).Scan(&count); err != nil {
+		return err
+	}
+	if count < w.cfg.RecordCount {
+		return fmt.Errorf("%s has %d records, expect %d", tableName, count, w.cfg.RecordCount)
+	}
+	return nil
+}
+
+// buildKey returns the key of the key number, the keys are hashed so that the inserts are
+// spread over the key space like the hashed insert order of YCSB.
+func buildKey(keyNum int64) string {
+	return "user" + strconv.FormatUint(fnvHash64(uint64(keyNum)), 10)
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func randValue(r *rand.Rand, length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = letters[r.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+func (w *Workloader) prepareStmts(ctx context.Context, s *ycsbState) error {
+	prepare := func(query string) (*sql.Stmt, error) {
+		return s.Conn.PrepareContext(ctx, convertToPQ(query, w.cfg.Driver))
+	}
+	var err error
+	if s.readStmt, err = prepare(fmt.Sprintf("SELECT * FROM %s WHERE YCSB_KEY = ?", tableName)); err != nil {
+		return err
+	}
+	if s.scanStmt, err = prepare(fmt.Sprintf("SELECT * FROM %s WHERE YCSB_KEY >= ? ORDER BY YCSB_KEY LIMIT ?", tableName)); err != nil {
+		return err
+	}
+	placeholders := strings.Repeat(", ?", w.cfg.FieldCount)
+	if s.insertStmt, err = prepare(fmt.Sprintf("INSERT INTO %s VALUES (?%s)", tableName, placeholders)); err != nil {
+		return err
+	}
+	s.updateStmts = make([]*sql.Stmt, w.cfg.FieldCount)
+	for i := range s.updateStmts {
+		if s.updateStmts[i], err = prepare(fmt.Sprintf("UPDATE %s SET %s = ? WHERE YCSB_KEY = ?", tableName, fieldName(i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ycsbState) closeStmts() {
+	for _, stmt := range append([]*sql.Stmt{s.readStmt, s.scanStmt, s.insertStmt}, s.updateStmts...) {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+	s.readStmt, s.scanStmt, s.insertStmt, s.updateStmts = nil, nil, nil, nil
+}
+
+// convertToPQ converts the placeholders to $1, $2... for postgres.
+func convertToPQ(query string, driver string) string {
+	if driver != "postgres" {
+		return query
+	}
+	var buf strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			buf.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
+
+// Run implements Workloader interface
+func (w *Workloader) Run(ctx context.Context, threadID int) error {
+	s := getState(ctx)
+	if err := s.Conn.PingContext(ctx); err != nil {
+		if err := s.RefreshConn(ctx); err != nil {
+			return err
+		}
+		s.closeStmts()
+	}
+	if s.readStmt == nil {
+		if err := w.prepareStmts(ctx, s); err != nil {
+			s.closeStmts()
+			return err
+		}
+	}
+
+	op := w.chooseOperation(s.R)
+	start := workload.StartTime(ctx)
+	err := op.action(ctx, s)
+	w.measurement.Measure(op.name, time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("%s failed %v", op.name, err)
+	}
+	return nil
+}
+
+func (w *Workloader) chooseOperation(r *rand.Rand) operation {
+	p := r.Float64()
+	for _, op := range w.ops {
+		if p < op.proportion {
+			return op
+		}
+		p -= op.proportion
+	}
+	return w.ops[len(w.ops)-1]
+}
+
+func (w *Workloader) nextKey(s *ycsbState) string {
+	return buildKey(s.chooser.next(s.R, atomic.LoadInt64(&w.insertCount)))
+}
+
+// drainRows reads all the fields of the rows
+func (s *ycsbState) drainRows(rows *sql.Rows) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(s.dest...); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (w *Workloader) runRead(ctx context.Context, s *ycsbState) error {
+	rows, err := s.readStmt.QueryContext(ctx, w.nextKey(s))
+	if err != nil {
+		return err
+	}
+	return s.drainRows(rows)
+}
+
+func (w *Workloader) runScan(ctx context.Context, s *ycsbState) error {
+	rows, err := s.scanStmt.QueryContext(ctx, w.nextKey(s), s.R.Intn(w.cfg.MaxScanLength)+1)
+	if err != nil {
+		return err
+	}
+	return s.drainRows(rows)
+}
+
+// runUpdate updates a random field of the record like YCSB without writing all the fields.
+func (w *Workloader) runUpdate(ctx context.Context, s *ycsbState) error {
+	return w.update(ctx, s, w.nextKey(s))
+}
+
+func (w *Workloader) update(ctx context.Context, s *ycsbState, key string) error {
+	_, err := s.updateStmts[s.R.Intn(w.cfg.FieldCount)].ExecContext(ctx, randValue(s.R, w.cfg.FieldLength), key)
+	return err
+}
+
+func (w *Workloader) runInsert(ctx context.Context, s *ycsbState) error {
+	keyNum := atomic.AddInt64(&w.insertSeq, 1) - 1
+	defer atomic.AddInt64(&w.insertCount, 1)
+	args := make([]interface{}, w.cfg.FieldCount+1)
+	args[0] = buildKey(keyNum)
+	for i := 1; i < len(args); i++ {
+		args[i] = randValue(s.R, w.cfg.FieldLength)
+	}
+	_, err := s.insertStmt.ExecContext(ctx, args...)
+	return err
+}
+
+func (w *Workloader) runReadModifyWrite(ctx context.Context, s *ycsbState) error {
+	key := w.nextKey(s)
+	rows, err := s.readStmt.QueryContext(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := s.drainRows(rows); err != nil {
+		return err
+	}
+	return w.update(ctx, s, key)
+}
+
+// Cleanup implements Workloader interface
+func (w *Workloader) Cleanup(ctx context.Context, threadID int) error {
+	if threadID != 0 {
+		return nil
+	}
+	return w.dropTable(ctx)
+}
+
+// Check implements Workloader interface
+func (w *Workloader) Check(ctx context.Context, threadID int) error {
+	return nil
+}
+
+func outputRtMeasurement(outputStyle string, prefix string, opMeasurement map[string]*measurement.Histogram) {
+	keys := make([]string, 0, len(opMeasurement))
+	for k := range opMeasurement {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := [][]string{}
+	for _, op := range keys {
+		hist := opMeasurement[op]
+		if !hist.Empty() {
+			line := []string{prefix, strings.ToUpper(op)}
+			line = append(line, hist.Summary()...)
+			lines = append(lines, line)
+		}
+	}
+	headers := []string{"Prefix", "Operation", "Takes(s)", "Count", "TPM", "Sum(ms)", "Avg(ms)", "50th(ms)", "90th(ms)", "95th(ms)", "99th(ms)", "99.9th(ms)", "Max(ms)"}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", headers, lines)
+	case util.OutputStyleTable:
+		util.RenderTable(headers, lines)
+	case util.OutputStyleJson:
+		util.RenderJson(headers, lines)
+	}
+
+	errLines := measurement.ErrorClassLines(prefix, opMeasurement)
+	if len(errLines) == 0 {
+		return
+	}
+	switch outputStyle {
+	case util.OutputStylePlain:
+		util.RenderString("%s%-6s - %s\n", measurement.ErrorClassHeaders, errLines)
+	case util.OutputStyleTable:
+		util.RenderTable(measurement.ErrorClassHeaders, errLines)
+	case util.OutputStyleJson:
+		util.RenderJson(measurement.ErrorClassHeaders, errLines)
+	}
+}
+
+// OutputStats implements Workloader interface
+func (w *Workloader) OutputStats(ifSummaryReport bool) {
+	w.measurement.Output(ifSummaryReport, w.cfg.OutputStyle, outputRtMeasurement)
+	if !ifSummaryReport {
+		return
+	}
+	if results := w.Results(); results != nil {
+		lines := [][]string{{util.FloatToOneString(results["throughput"])}}
+		switch w.cfg.OutputStyle {
+		case util.OutputStylePlain:
+			util.RenderString("throughput(ops/s): %s\n", nil, lines)
+		case util.OutputStyleTable:
+			util.RenderTable([]string{"throughput(ops/s)"}, lines)
+		case util.OutputStyleJson:
+			util.RenderJson([]string{"throughput(ops/s)"}, lines)
+		}
+	}
+}
+
+// Results implements workload.ResultProvider, it returns the throughput of the successful
+// operations per second, or nil if no operation is measured.
+func (w *Workloader) Results() map[string]float64 {
+	var (
+		totalOps float64
+		measured bool
+	)
+	for name, hist := range w.measurement.OpSumMeasurement {
+		if !strings.HasSuffix(name, "_ERR") && !hist.Empty() {
+			totalOps += hist.GetInfo().Ops
+			measured = true
+		}
+	}
+	if !measured {
+		return nil
+	}
+	return map[string]float64{"throughput": totalOps}
+}
+
+// Measurement returns the response time measurement of the workload.
+func (w *Workloader) Measurement() *measurement.Measurement {
+	return w.measurement
+}
+
+// DBName returns the name of test db.
+func (w *Workloader) DBName() string {
+	return w.cfg.DBName
+}
+
+func (w *Workloader) IsPlanReplayerDumpEnabled() bool {
+	return false
+}
+
+func (w *Workloader) PreparePlanReplayerDump() error {
+	return nil
+}
+
+func (w *Workloader) FinishPlanReplayerDump() error {
+	return nil
+}
+
+func (w *Workloader) Exec(sql string) error {
+	return nil
+}